@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"math/rand"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,6 +26,7 @@ import (
 	tavorFuzzStrategy "github.com/zimmski/tavor/fuzz/strategy"
 	"github.com/zimmski/tavor/graph"
 	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/parser"
 	tavorReduceStrategy "github.com/zimmski/tavor/reduce/strategy"
 	"github.com/zimmski/tavor/token"
@@ -50,6 +53,7 @@ type options struct {
 	Global struct {
 		Seed      int64 `long:"seed" description:"Seed for all the randomness"`
 		MaxRepeat int   `long:"max-repeat" description:"How many times loops and repetitions should be repeated" default:"2"`
+		NoUnroll  bool  `long:"no-unroll" description:"Do not unroll loops but expand them on demand"`
 	} `group:"Global options"`
 
 	Format struct {
@@ -75,12 +79,20 @@ type options struct {
 			Script string `long:"script" description:"Execute this binary which gets fed with the generation and should return feedback"`
 
 			ExitOnError bool `long:"exit-on-error" description:"Exit if an execution fails"`
+
+			FeedbackFile flags.Filename `long:"feedback-file" description:"Read the coverage of every execution from this file, which is either a coverage bitmap or a Go coverage profile, and give it as feedback to feedback fuzzing strategies"`
 		}
 
 		Filter optsFuzzingFilters
 
-		Strategy       fuzzStrategy `long:"strategy" description:"The fuzzing strategy" default:"random"`
-		ListStrategies bool         `long:"list-strategies" description:"List all available fuzzing strategies"`
+		Strategy       fuzzStrategy `long:"strategy" description:"The fuzzing strategy with optional options, e.g. random:count=10" default:"random"`
+		ListStrategies bool         `long:"list-strategies" description:"List all available fuzzing strategies with their options"`
+
+		From string `long:"from" description:"Start the AllPermutations fuzzing strategy with the permutation of this index"`
+		To   string `long:"to" description:"Stop the AllPermutations fuzzing strategy before the permutation of this index"`
+
+		Corpus    flags.Filename `long:"corpus" description:"Mutate the inputs of this folder instead of using the random fuzzing strategy or start the population of the Genetic fuzzing strategy with them"`
+		Mutations int            `long:"mutations" description:"How many mutations of the corpus should be generated" default:"100"`
 
 		ResultFolder     flags.Filename `long:"result-folder" description:"Save every fuzzing result with the MD5 checksum as filename in this folder"`
 		ResultExtensions string         `long:"result-extension" description:"If result-folder is used this will be the extension of every filename"`
@@ -108,8 +120,8 @@ type options struct {
 
 		InputFile flags.Filename `long:"input-file" description:"Input file which gets parsed, validated and delta-debugged via the format file" required:"true"`
 
-		Strategy       reduceStrategy `long:"strategy" description:"The reducing strategy" default:"Linear"`
-		ListStrategies bool           `long:"list-strategies" description:"List all available reducing strategies"`
+		Strategy       reduceStrategy `long:"strategy" description:"The reducing strategy with optional options" default:"Linear"`
+		ListStrategies bool           `long:"list-strategies" description:"List all available reducing strategies with their options"`
 
 		ResultSeparator string `long:"result-separator" description:"Separates result outputs of each reducing step" default:"\n"`
 	} `command:"reduce" description:"Reduce the given input file"`
@@ -120,8 +132,9 @@ type options struct {
 }
 
 type optsFuzzingFilters struct {
-	Filters     fuzzFilters `long:"filter" description:"Fuzzing filter to apply"`
-	ListFilters bool        `long:"list-filters" description:"List all available fuzzing filters"`
+	Filters        fuzzFilters `long:"filter" description:"Fuzzing filter with optional options and token definition selectors to apply, e.g. PositiveBoundaryValueAnalysis:max=9@Header.Length"`
+	FilterPipeline bool        `long:"filter-pipeline" description:"Apply the fuzzing filters as pipeline, later filters are also applied onto the replacements of earlier filters"`
+	ListFilters    bool        `long:"list-filters" description:"List all available fuzzing filters with their options"`
 }
 
 var execArgumentTypes = []string{
@@ -150,44 +163,54 @@ type fuzzFilter string
 type fuzzFilters []fuzzFilter
 
 func (s fuzzFilters) Complete(match string) []flags.Completion {
-	var items []flags.Completion
-
-	for _, name := range tavorFuzzFilter.List() {
-		if strings.HasPrefix(name, match) {
-			items = append(items, flags.Completion{
-				Item: name,
-			})
-		}
-	}
-
-	return items
+	return completeOptions(match, tavorFuzzFilter.List(), tavorFuzzFilter.Options)
 }
 
 type fuzzStrategy string
 
 func (s *fuzzStrategy) Complete(match string) []flags.Completion {
-	var items []flags.Completion
-
-	for _, name := range tavorFuzzStrategy.List() {
-		if strings.HasPrefix(name, match) {
-			items = append(items, flags.Completion{
-				Item: name,
-			})
-		}
-	}
-
-	return items
+	return completeOptions(match, tavorFuzzStrategy.List(), tavorFuzzStrategy.Options)
 }
 
 type reduceStrategy string
 
 func (s *reduceStrategy) Complete(match string) []flags.Completion {
+	return completeOptions(match, tavorReduceStrategy.List(), tavorReduceStrategy.Options)
+}
+
+// completeOptions completes a specification like "name:key=value,key=value" with the given names and their options
+func completeOptions(match string, names []string, options func(name string) []option.Option) []flags.Completion {
 	var items []flags.Completion
 
-	for _, name := range tavorReduceStrategy.List() {
-		if strings.HasPrefix(name, match) {
+	// token definitions of selectors are unknown without a format file
+	if strings.Contains(match, "@") {
+		return nil
+	}
+
+	i := strings.Index(match, ":")
+	if i == -1 {
+		for _, name := range names {
+			if strings.HasPrefix(name, match) {
+				items = append(items, flags.Completion{
+					Item: name,
+				})
+			}
+		}
+
+		return items
+	}
+
+	// complete the option of the last argument
+	prefix := match
+	if j := strings.LastIndexAny(match, ":,"); j != -1 {
+		prefix = match[:j+1]
+	}
+
+	for _, o := range options(match[:i]) {
+		if item := prefix + o.Name + "="; strings.HasPrefix(item, match) {
 			items = append(items, flags.Completion{
-				Item: name,
+				Item:        item,
+				Description: o.Description,
 			})
 		}
 	}
@@ -195,6 +218,17 @@ func (s *reduceStrategy) Complete(match string) []flags.Completion {
 	return items
 }
 
+// printOptions prints the given names with their options
+func printOptions(names []string, options func(name string) []option.Option) {
+	for _, name := range names {
+		fmt.Println(name)
+
+		for _, o := range options(name) {
+			fmt.Printf("    %s\n", o)
+		}
+	}
+}
+
 func checkArguments(args []string, opts *options) (string, exitCodeType) {
 	p := flags.NewNamedParser("tavor", flags.None)
 
@@ -216,15 +250,11 @@ func checkArguments(args []string, opts *options) (string, exitCodeType) {
 
 		return "", exitCodeOk
 	} else if opts.Fuzz.Filter.ListFilters || opts.Graph.Filter.ListFilters {
-		for _, name := range tavorFuzzFilter.List() {
-			fmt.Println(name)
-		}
+		printOptions(tavorFuzzFilter.List(), tavorFuzzFilter.Options)
 
 		return "", exitCodeOk
 	} else if opts.Fuzz.ListStrategies {
-		for _, name := range tavorFuzzStrategy.List() {
-			fmt.Println(name)
-		}
+		printOptions(tavorFuzzStrategy.List(), tavorFuzzStrategy.Options)
 
 		return "", exitCodeOk
 	} else if opts.Fuzz.Exec.ListExecArgumentTypes || opts.Reduce.Exec.ListExecArgumentTypes {
@@ -234,9 +264,7 @@ func checkArguments(args []string, opts *options) (string, exitCodeType) {
 
 		return "", exitCodeOk
 	} else if opts.Reduce.ListStrategies {
-		for _, name := range tavorReduceStrategy.List() {
-			fmt.Println(name)
-		}
+		printOptions(tavorReduceStrategy.List(), tavorReduceStrategy.Options)
 
 		return "", exitCodeOk
 	}
@@ -314,23 +342,47 @@ func exitError(format string, args ...interface{}) exitCodeType {
 	return exitCodeError
 }
 
-func applyFilters(opts *options, filterNames []fuzzFilter, doc token.Token) (token.Token, error) {
-	if len(filterNames) > 0 {
+func applyFilters(opts *options, filterOpts optsFuzzingFilters, doc token.Token) (token.Token, error) {
+	if filterNames := filterOpts.Filters; len(filterNames) > 0 {
 		var err error
-		var filters []tavorFuzzFilter.Filter
+		var filters []tavorFuzzFilter.SelectedFilter
 
 		for _, name := range filterNames {
-			filt, err := tavorFuzzFilter.New(string(name))
+			specification := string(name)
+
+			var selectors []tavorFuzzFilter.Selector
+			if i := strings.LastIndex(specification, "@"); i != -1 {
+				selectors, err = tavorFuzzFilter.NewSelectors(specification[i+1:])
+				if err != nil {
+					return nil, err
+				}
+
+				specification = specification[:i]
+			}
+
+			filterName, filterArguments, err := option.Split(specification)
 			if err != nil {
 				return nil, err
 			}
 
-			filters = append(filters, filt)
+			filt, err := tavorFuzzFilter.NewWithOptions(filterName, filterArguments)
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, tavorFuzzFilter.SelectedFilter{
+				Filter:    filt,
+				Selectors: selectors,
+			})
 
 			log.Infof("using %s fuzzing filter", name)
 		}
 
-		doc, err = tavorFuzzFilter.ApplyFilters(filters, doc)
+		if filterOpts.FilterPipeline {
+			doc, err = tavorFuzzFilter.ApplyFilterPipeline(filters, doc)
+		} else {
+			doc, err = tavorFuzzFilter.ApplySelectedFilters(filters, doc)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -360,6 +412,7 @@ func mainCmd(args []string) exitCodeType {
 	}
 
 	tavor.MaxRepeat = opts.Global.MaxRepeat
+	tavor.UnrollLoops = !opts.Global.NoUnroll
 
 	log.Infof("open file %s", opts.Format.FormatFile)
 
@@ -400,28 +453,131 @@ func mainCmd(args []string) exitCodeType {
 
 	switch command {
 	case "fuzz":
-		doc, err = applyFilters(opts, opts.Fuzz.Filter.Filters, doc)
+		doc, err = applyFilters(opts, opts.Fuzz.Filter, doc)
 		if err != nil {
 			return exitError("cannot apply filters: %v", err)
 		}
 
-		log.Infof("counted %d overall permutations", doc.PermutationsAll())
+		log.Infof("counted %s overall permutations", doc.PermutationsAllBig())
+
+		strategyName, strategyArguments, err := option.Split(string(opts.Fuzz.Strategy))
+		if err != nil {
+			return exitError(err.Error())
+		}
 
-		strat, err := tavorFuzzStrategy.New(string(opts.Fuzz.Strategy))
+		strat, err := tavorFuzzStrategy.NewWithOptions(strategyName, strategyArguments)
 		if err != nil {
 			return exitError(err.Error())
 		}
 
-		log.Infof("using %s fuzzing strategy", opts.Fuzz.Strategy)
+		if opts.Fuzz.From != "" || opts.Fuzz.To != "" {
+			if strategyName != "AllPermutations" {
+				return exitError("from and to can only be used with the AllPermutations fuzzing strategy")
+			}
+
+			from := big.NewInt(0)
+			if opts.Fuzz.From != "" {
+				if _, ok := from.SetString(opts.Fuzz.From, 10); !ok || from.Sign() < 0 {
+					return exitError("from %q is not a valid permutation index", opts.Fuzz.From)
+				}
+			}
+
+			var to *big.Int
+			if opts.Fuzz.To != "" {
+				to = new(big.Int)
+				if _, ok := to.SetString(opts.Fuzz.To, 10); !ok || to.Sign() < 0 {
+					return exitError("to %q is not a valid permutation index", opts.Fuzz.To)
+				}
+			}
+
+			log.Infof("using permutations from %s to %s", from, to)
+
+			strat = tavorFuzzStrategy.NewAllPermutationsRange(from, to)
+		}
+
+		// the strategy is nil if it is no feedback fuzzing strategy
+		feedbackStrat, _ := tavorFuzzStrategy.NewFeedbackWithOptions(strategyName, strategyArguments)
+		if feedbackStrat != nil && opts.Fuzz.Exec.Exec == "" && opts.Fuzz.Exec.Script == "" {
+			return exitError("the %s fuzzing strategy does not end on its own and needs the feedback of exec or script", strategyName)
+		}
+
+		usedStrategy := string(opts.Fuzz.Strategy)
+
+		var seeds []*big.Int
+
+		if opts.Fuzz.Corpus != "" {
+			if opts.Fuzz.From != "" || opts.Fuzz.To != "" {
+				return exitError("from and to cannot be used with a corpus")
+			}
+			if strategyName != "Genetic" && strategyName != "random" {
+				return exitError("the %s fuzzing strategy cannot be used with a corpus", strategyName)
+			}
+			if strategyName == "random" && len(strategyArguments) != 0 {
+				return exitError("the options of the random fuzzing strategy cannot be used with a corpus, the number of mutations is defined by --mutations")
+			}
+
+			seeds, err = readCorpus(doc, string(opts.Fuzz.Corpus))
+			if err != nil {
+				return exitError("cannot read corpus: %v", err)
+			}
+
+			if strategyName == "Genetic" {
+				// the arguments were already validated by creating the strategy
+				values, _ := option.Parse(tavorFuzzStrategy.Options(strategyName), strategyArguments)
+
+				log.Infof("evolve a population of %d with %d inputs of the corpus", values.Int("population"), len(seeds))
+
+				feedbackStrat = tavorFuzzStrategy.NewGenetic(seeds, values.Int("population"))
+				strat = tavorFuzzStrategy.WithoutFeedback(feedbackStrat)
+			} else {
+				log.Infof("mutate %d inputs of the corpus %s", len(seeds), opts.Fuzz.Corpus)
+
+				strat = tavorFuzzStrategy.NewMutation(seeds, opts.Fuzz.Mutations)
+				feedbackStrat = nil
+
+				usedStrategy = "mutation"
+			}
+		}
+
+		log.Infof("using %s fuzzing strategy", usedStrategy)
 
 		folder := opts.Fuzz.ResultFolder
 		if len(folder) > 0 && folder[len(folder)-1] != '/' {
 			folder += "/"
 		}
 
-		ch, err := strat(doc, r)
-		if err != nil {
-			return exitError(err.Error())
+		var ch chan struct{}
+		var feedback chan<- tavorFuzzStrategy.Feedback
+
+		if feedbackStrat != nil && (opts.Fuzz.Exec.Exec != "" || opts.Fuzz.Exec.Script != "") {
+			log.Info("give feedback to the fuzzing strategy")
+
+			ch, feedback, err = feedbackStrat(doc, r)
+			if err != nil {
+				return exitError(err.Error())
+			}
+		} else {
+			ch, err = strat(doc, r)
+			if err != nil {
+				return exitError(err.Error())
+			}
+		}
+
+		readFeedback := func(score float64) (tavorFuzzStrategy.Feedback, error) {
+			f := tavorFuzzStrategy.Feedback{
+				Score: score,
+			}
+
+			if opts.Fuzz.Exec.FeedbackFile != "" {
+				coverage, err := readCoverageFile(string(opts.Fuzz.Exec.FeedbackFile))
+				if err != nil {
+					return f, fmt.Errorf("Could not read feedback file: %s", err)
+				}
+
+				f.Coverage = coverage
+			}
+
+			return f, nil
 		}
 
 		if opts.Fuzz.Exec.Exec != "" {
@@ -483,6 +639,12 @@ func mainCmd(args []string) exitCodeType {
 
 				execCommand := exec.Command(execs[0], execs[1:]...)
 
+				if opts.Fuzz.Exec.FeedbackFile != "" {
+					if err := os.Remove(string(opts.Fuzz.Exec.FeedbackFile)); err != nil && !os.IsNotExist(err) {
+						return exitError("Could not remove feedback file: %s", err)
+					}
+				}
+
 				if string(opts.Fuzz.Exec.ExecArgumentType) == "environment" {
 					tmp, err = writeTmpFile(docOut)
 					if err != nil {
@@ -634,6 +796,15 @@ func mainCmd(args []string) exitCodeType {
 					}
 				}
 
+				if feedback != nil {
+					f, err := readFeedback(0)
+					if err != nil {
+						return exitError(err.Error())
+					}
+
+					feedback <- f
+				}
+
 				ch <- i
 
 				stepID++
@@ -694,6 +865,17 @@ func mainCmd(args []string) exitCodeType {
 					return exitError("Could not read stdout from script: %s", err)
 				}
 
+				// the feedback can be followed by a score
+				var score float64
+				if fields := strings.Fields(feed); len(fields) == 2 {
+					score, err = strconv.ParseFloat(fields[1], 64)
+					if err != nil {
+						return exitError("Score from script is not a number: %s", feed)
+					}
+
+					feed = fields[0] + "\n"
+				}
+
 				switch feed {
 				case "YES\n":
 					log.Infof("Same output")
@@ -707,6 +889,15 @@ func mainCmd(args []string) exitCodeType {
 					return exitError("Feedback from script was not YES nor NO: %s", feed)
 				}
 
+				if feedback != nil {
+					f, err := readFeedback(score)
+					if err != nil {
+						return exitError(err.Error())
+					}
+
+					feedback <- f
+				}
+
 				ch <- i
 			}
 
@@ -768,7 +959,7 @@ func mainCmd(args []string) exitCodeType {
 			}
 		}
 	case "graph":
-		doc, err = applyFilters(opts, opts.Graph.Filter.Filters, doc)
+		doc, err = applyFilters(opts, opts.Graph.Filter, doc)
 		if err != nil {
 			return exitError("cannot apply filters: %v", err)
 		}
@@ -806,7 +997,12 @@ func mainCmd(args []string) exitCodeType {
 		}
 
 		if command == "reduce" {
-			strat, err := tavorReduceStrategy.New(string(opts.Reduce.Strategy))
+			strategyName, strategyArguments, err := option.Split(string(opts.Reduce.Strategy))
+			if err != nil {
+				return exitError(err.Error())
+			}
+
+			strat, err := tavorReduceStrategy.NewWithOptions(strategyName, strategyArguments)
 			if err != nil {
 				return exitError(err.Error())
 			}
@@ -1239,6 +1435,103 @@ func mainCmd(args []string) exitCodeType {
 	return exitCodeOk
 }
 
+// readCorpus parses every file of the given folder with the given token graph and returns the permutations of the valid files
+func readCorpus(doc token.Token, folder string) ([]*big.Int, error) {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var seeds []*big.Int
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		name := filepath.Join(folder, file.Name())
+
+		input, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		errs := parser.ParseInternal(doc, input)
+
+		if err := input.Close(); err != nil {
+			return nil, err
+		}
+
+		if len(errs) != 0 {
+			log.Warnf("skip invalid input %s: %v", name, errs[0])
+
+			continue
+		}
+
+		seed, err := token.PermutationIndex(doc)
+		if err != nil {
+			log.Warnf("skip input %s: %v", name, err)
+
+			continue
+		}
+
+		seeds = append(seeds, seed)
+	}
+
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("no valid inputs in %s", folder)
+	}
+
+	return seeds, nil
+}
+
+// readCoverageFile reads the covered behaviour of an execution which is either given as Go coverage profile or as coverage bitmap
+func readCoverageFile(file string) ([]string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var coverage []string
+
+	if bytes.HasPrefix(data, []byte("mode:")) {
+		// Go coverage profile with lines of "file:startLine.startColumn,endLine.endColumn numberOfStatements count"
+		for _, line := range strings.Split(string(data), "\n")[1:] {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				continue
+			}
+
+			if count, err := strconv.Atoi(fields[2]); err == nil && count > 0 {
+				coverage = append(coverage, fields[0])
+			}
+		}
+
+		return coverage, nil
+	}
+
+	// coverage bitmap where every byte holds the hit count of one edge
+	for i, b := range data {
+		if b == 0 {
+			continue
+		}
+
+		// hit counts are put into buckets of powers of two
+		bucket := 0
+		for ; b > 1; b >>= 1 {
+			bucket++
+		}
+
+		coverage = append(coverage, fmt.Sprintf("%d:%d", i, bucket))
+	}
+
+	return coverage, nil
+}
+
 func main() {
 	exitCode := int(mainCmd(os.Args[1:]))
 
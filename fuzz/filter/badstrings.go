@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func init() {
+	RegisterOptions("BadStrings", []option.Option{
+		{
+			Name:        "builtin",
+			Type:        option.Bool,
+			Default:     "true",
+			Description: "Use the built-in dictionary of bad strings",
+		},
+		{
+			Name:        "wordlist",
+			Type:        option.String,
+			Default:     "",
+			Description: "Wordlist files which extend the dictionary, separated by the path list separator of the OS",
+		},
+	}, func(values option.Values) (Filter, error) {
+		var dictionary []string
+
+		if values.Bool("builtin") {
+			dictionary = append(dictionary, BadStringsDictionary...)
+		}
+
+		for _, file := range filepath.SplitList(values.String("wordlist")) {
+			words, err := ReadWordlistFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			dictionary = append(dictionary, words...)
+		}
+
+		if len(dictionary) == 0 {
+			return nil, &option.Error{
+				Message: "the dictionary of bad strings is empty",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewBadStringsDictionary(dictionary), nil
+	})
+}
+
+// BadStringsDictionary holds the built-in dictionary of the BadStrings filter
+var BadStringsDictionary = []string{
+	// format specifiers
+	"%s%s%s%s%s%s%s%s%s%s",
+	"%n%n%n%n%n%n%n%n%n%n",
+	"%x%x%x%x%x%x%x%x%x%x",
+	"%p%p%p%p%p%p%p%p%p%p",
+	"%99999999999s",
+	"{0}{1}{2}",
+	"${7*7}",
+	// SQL metacharacters
+	"'",
+	"\"",
+	"' OR '1'='1",
+	"' OR 1=1 --",
+	"'; DROP TABLE users; --",
+	"1; SELECT 1",
+	// shell metacharacters
+	"; id",
+	"| id",
+	"&& id",
+	"`id`",
+	"$(id)",
+	"\nid\n",
+	"../../../../../../../../etc/passwd",
+	// very long strings
+	strings.Repeat("A", 256),
+	strings.Repeat("A", 1025),
+	strings.Repeat("A", 65537),
+	// Unicode confusables
+	"\u0430dmin",
+	"\uff41\uff44\uff4d\uff49\uff4e",
+	"\u0455\u0441r\u0456\u0440t",
+	"\u212a",
+	// RTL overrides and invisible characters
+	"\u202etxt.exe",
+	"\u202d\u202e",
+	"\u200f\u200e",
+	"\u200b",
+	"\ufeff",
+	// NUL bytes
+	"\x00",
+	"a\x00b",
+	// invalid UTF-8
+	"\xff",
+	"\xc3\x28",
+	"\xc0\xaf",
+	"\xed\xa0\x80",
+	"\xf8\x88\x80\x80\x80",
+}
+
+// NewBadStrings returns a fuzzing filter which offers the bad strings of the built-in dictionary as alternatives to strings.
+// See NewBadStringsDictionary for how the filter transforms the token graph.
+func NewBadStrings() Filter {
+	return NewBadStringsDictionary(BadStringsDictionary)
+}
+
+// NewBadStringsDictionary returns a fuzzing filter which offers the bad strings of the given dictionary as alternatives to strings.
+// This filter searches the token graph for constant string tokens and repeats of character classes, which will be transformed to a One token holding the original token as first alternative followed by every string of the dictionary. The built-in dictionary BadStringsDictionary holds known-dangerous strings like format specifiers, SQL and shell metacharacters, very long strings, Unicode confusables, RTL overrides, NUL bytes and invalid UTF-8.
+func NewBadStringsDictionary(dictionary []string) Filter {
+	var words []string
+	seen := make(map[string]struct{}, len(dictionary))
+	for _, word := range dictionary {
+		if _, ok := seen[word]; !ok {
+			seen[word] = struct{}{}
+			words = append(words, word)
+		}
+	}
+
+	return func(tok token.Token) (token.Token, error) {
+		var value string
+
+		switch t := tok.(type) {
+		case *primitives.ConstantString:
+			value = t.String()
+		case *lists.Repeat:
+			c, _ := t.InternalGet(0)
+			if _, ok := c.(*primitives.CharacterClass); !ok {
+				return nil, nil
+			}
+		default:
+			return nil, nil
+		}
+
+		replacements := []token.Token{tok}
+
+		for _, word := range words {
+			if word == value {
+				continue
+			}
+
+			c := primitives.NewConstantString(word)
+
+			replacements = append(replacements, c)
+		}
+
+		return lists.NewOne(replacements...), nil
+	}
+}
+
+// ReadWordlist reads a wordlist with one word per line.
+// Empty lines and lines starting with "#" are ignored. Lines starting with a double quote are unquoted as Go string literal, which allows for example words with NUL bytes or newlines. The error return argument is not nil, if the wordlist could not be read or a quoted word is not valid.
+func ReadWordlist(r io.Reader) ([]string, error) {
+	var words []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		word := scanner.Text()
+
+		switch {
+		case word == "" || strings.HasPrefix(word, "#"):
+			continue
+		case strings.HasPrefix(word, "\""):
+			w, err := strconv.Unquote(word)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted word in line %d: %s", line, word)
+			}
+
+			word = w
+		}
+
+		words = append(words, word)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// ReadWordlistFile reads a wordlist file with one word per line.
+// See ReadWordlist for the format of the file.
+func ReadWordlistFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	words, err := ReadWordlist(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read wordlist %s: %v", file, err)
+	}
+
+	return words, nil
+}
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestBadStringsFilter(t *testing.T) {
+	filt := NewBadStringsDictionary([]string{"'", "\x00", "'", "a"})
+
+	// constant string
+	{
+		root := primitives.NewConstantString("a")
+		replacement, err := filt(root)
+		Nil(t, err)
+		Equal(t, lists.NewOne(
+			root,
+			primitives.NewConstantString("'"),
+			primitives.NewConstantString("\x00"),
+		), replacement)
+	}
+	// repeat of a character class
+	{
+		root := lists.NewRepeat(primitives.NewCharacterClass("a-z"), 1, 3)
+		replacement, err := filt(root)
+		Nil(t, err)
+		Equal(t, lists.NewOne(
+			root,
+			primitives.NewConstantString("'"),
+			primitives.NewConstantString("\x00"),
+			primitives.NewConstantString("a"),
+		), replacement)
+	}
+	// other tokens are not filtered
+	for _, root := range []token.Token{
+		primitives.NewCharacterClass("a-z"),
+		primitives.NewConstantInt(1),
+		lists.NewRepeat(primitives.NewConstantString("a"), 1, 3),
+	} {
+		replacement, err := filt(root)
+		Nil(t, err)
+		Nil(t, replacement)
+	}
+	// generated tokens are not filtered again
+	{
+		root := lists.NewAll(
+			primitives.NewConstantString("a"),
+			lists.NewRepeat(primitives.NewCharacterClass("0-9"), 1, 1),
+		)
+
+		rootNew, err := ApplyFilters([]Filter{NewBadStringsDictionary([]string{"'", "\x00"})}, root)
+		Nil(t, err)
+		Equal(t, 36, rootNew.PermutationsAll())
+		Equal(t, "a0", rootNew.String())
+	}
+	// the built-in dictionary
+	{
+		root := primitives.NewConstantString("a")
+		replacement, err := NewBadStrings()(root)
+		Nil(t, err)
+		Equal(t, len(BadStringsDictionary)+1, replacement.Permutations())
+	}
+}
+
+func TestReadWordlist(t *testing.T) {
+	words, err := ReadWordlist(strings.NewReader("# comment\n\nadmin\n\"a\\x00b\"\n' OR ''='\n"))
+	Nil(t, err)
+	Equal(t, []string{"admin", "a\x00b", "' OR ''='"}, words)
+
+	words, err = ReadWordlist(strings.NewReader("admin\n\"a\n"))
+	Nil(t, words)
+	NotNil(t, err)
+}
+
+func TestBadStringsFilterOptions(t *testing.T) {
+	f, err := ioutil.TempFile("", "tavor-wordlist")
+	Nil(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("admin\nroot\n")
+	Nil(t, err)
+	Nil(t, f.Close())
+
+	filt, err := NewWithOptions("BadStrings", map[string]string{
+		"builtin":  "false",
+		"wordlist": f.Name(),
+	})
+	Nil(t, err)
+
+	root := primitives.NewConstantString("a")
+	replacement, err := filt(root)
+	Nil(t, err)
+	Equal(t, lists.NewOne(
+		root,
+		primitives.NewConstantString("admin"),
+		primitives.NewConstantString("root"),
+	), replacement)
+
+	filt, err = NewWithOptions("BadStrings", map[string]string{
+		"builtin": "false",
+	})
+	Nil(t, filt)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	filt, err = NewWithOptions("BadStrings", map[string]string{
+		"wordlist": f.Name() + ".missing",
+	})
+	Nil(t, filt)
+	NotNil(t, err)
+}
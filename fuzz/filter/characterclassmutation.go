@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"unicode/utf8"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func init() {
+	Register("CharacterClassMutation", NewCharacterClassMutation())
+}
+
+// characterClassMutationControls holds control characters which are added if they are not part of the character class
+var characterClassMutationControls = []string{
+	"\x00",
+	"\x01",
+	"\t",
+	"\n",
+	"\r",
+	"\x1b",
+	"\x7f",
+	"\u0085",
+}
+
+// characterClassMutationMultiByte holds valid UTF-8 sequences with more than one byte
+var characterClassMutationMultiByte = []string{
+	"\u00e9",
+	"\u20ac",
+	"\U0001f600",
+	"\ufffd",
+}
+
+// characterClassMutationInvalid holds invalid UTF-8 sequences
+var characterClassMutationInvalid = []string{
+	"\x80",
+	"\xc3",
+	"\xe2\x82",
+	"\xc0\x80",
+	"\xed\xa0\x80",
+	"\xf4\x90\x80\x80",
+	"\xff",
+}
+
+// NewCharacterClassMutation returns a fuzzing filter which mutates character classes.
+// This filter searches the token graph for character class tokens which will be transformed to a One token holding the original token as first alternative followed by the characters just outside every character and range of the class, control characters, multi-byte UTF-8 sequences and invalid UTF-8 sequences. Only characters which are not part of the class are added. For instance, the character class [a-z] gets amongst others the characters '`' and '{'. In contrast to the boundary-value analysis filters, this filter targets the lexers of the tested parsers.
+func NewCharacterClassMutation() Filter {
+	return func(tok token.Token) (token.Token, error) {
+		t, ok := tok.(*primitives.CharacterClass)
+		if !ok {
+			return nil, nil
+		}
+
+		replacements := []token.Token{tok}
+
+		seen := make(map[string]struct{})
+		add := func(s string) {
+			if _, ok := seen[s]; ok {
+				return
+			}
+			seen[s] = struct{}{}
+
+			if r, size := utf8.DecodeRuneInString(s); size == len(s) && r != utf8.RuneError && t.Contains(r) {
+				return
+			}
+
+			c := primitives.NewConstantString(s)
+
+			replacements = append(replacements, c)
+		}
+
+		// characters just outside of the class
+		outside := func(r rune) {
+			if utf8.ValidRune(r) {
+				add(string(r))
+			}
+		}
+
+		for _, c := range t.Characters() {
+			outside(c - 1)
+			outside(c + 1)
+		}
+		for _, r := range t.Ranges() {
+			outside(r[0] - 1)
+			outside(r[1] + 1)
+		}
+
+		for _, s := range characterClassMutationControls {
+			add(s)
+		}
+		for _, s := range characterClassMutationMultiByte {
+			add(s)
+		}
+		for _, s := range characterClassMutationInvalid {
+			add(s)
+		}
+
+		return lists.NewOne(replacements...), nil
+	}
+}
@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestCharacterClassMutationFilter(t *testing.T) {
+	values := func(tok token.Token) []string {
+		var vs []string
+
+		one := tok.(*lists.One)
+		for i := 1; i < one.InternalLen(); i++ {
+			c, _ := one.InternalGet(i)
+
+			vs = append(vs, c.String())
+		}
+
+		return vs
+	}
+
+	filt := NewCharacterClassMutation()
+
+	// range
+	{
+		root := primitives.NewCharacterClass("a-z")
+		replacement, err := filt(root)
+		Nil(t, err)
+
+		c, _ := replacement.(*lists.One).InternalGet(0)
+		True(t, Exactly(t, root, c))
+
+		vs := values(replacement)
+		Equal(t, []string{"`", "{", "\x00", "\x01", "\t", "\n", "\r", "\x1b", "\x7f", "\u0085"}, vs[:10])
+		Contains(t, vs, "\U0001f600")
+		Contains(t, vs, "\xc0\x80")
+	}
+	// characters and ranges next to each other
+	{
+		root := primitives.NewCharacterClass(`\x5fa-z0-9\n`)
+		replacement, err := filt(root)
+		Nil(t, err)
+
+		vs := values(replacement)
+		Equal(t, []string{"^", "`", "\t", "\v", "{", "/", ":", "\x00"}, vs[:8])
+		NotContains(t, vs, "\n")
+	}
+	// characters just outside which are not valid characters are skipped
+	{
+		root := primitives.NewCharacterClass(`\x00-\x{d7ff}`)
+		replacement, err := filt(root)
+		Nil(t, err)
+
+		vs := values(replacement)
+		Equal(t, []string{"\U0001f600", "\ufffd", "\x80"}, vs[:3])
+	}
+	// other tokens are not filtered
+	{
+		replacement, err := filt(primitives.NewConstantString("a"))
+		Nil(t, err)
+		Nil(t, replacement)
+	}
+	// generated tokens are not filtered again
+	{
+		root := lists.NewRepeat(primitives.NewCharacterClass("a"), 2, 2)
+
+		rootNew, err := ApplyFilters([]Filter{NewCharacterClassMutation()}, root)
+		Nil(t, err)
+
+		c, _ := rootNew.(*lists.Repeat).InternalGet(0)
+		True(t, c.Permutations() > 1)
+		Equal(t, "aa", rootNew.String())
+	}
+}
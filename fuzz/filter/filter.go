@@ -6,14 +6,30 @@ import (
 
 	"github.com/zimmski/container/list/linkedlist"
 
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/primitives"
 )
 
 // Filter defines a fuzzing filter
 // The function applies the fuzzing filter onto the token and returns a replacement token, or nil if there is no replacement. If a fatal error is encountered the error return argument is not nil.
 type Filter func(tok token.Token) (token.Token, error)
 
+// NewWithOptionsFunc returns a fuzzing filter instance for the given option values.
+// The error return argument is not nil, if the values are not valid for the filter.
+type NewWithOptionsFunc func(values option.Values) (Filter, error)
+
+type filterOptions struct {
+	options []option.Option
+	new     NewWithOptionsFunc
+}
+
+// MaxDepth defines how many filter replacements can be nested.
+// Tokens which are part of this many filter replacements are not filtered any more, which prevents infinite filtering, e.g. of filters which generate tokens that they filter again.
+var MaxDepth = 10
+
 var filterLookup = make(map[string]Filter)
+var filterOptionsLookup = make(map[string]filterOptions)
 
 // New returns a new fuzzing filter instance given the registered name of the filter.
 // The error return argument is not nil, if the name does not exist in the registered fuzzing filter list.
@@ -52,16 +68,133 @@ func Register(name string, filt Filter) {
 	filterLookup[name] = filt
 }
 
+// Options returns the declared options of the fuzzing filter with the given registered name.
+func Options(name string) []option.Option {
+	return filterOptionsLookup[name].options
+}
+
+// NewWithOptions returns a new fuzzing filter instance given the registered name of the filter and the arguments for its options.
+// Options which are not given as argument have their default value. The error return argument is not nil, if the name does not exist in the registered fuzzing filter list or if the arguments are not valid for the options of the filter.
+func NewWithOptions(name string, arguments map[string]string) (Filter, error) {
+	filt, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := option.Parse(Options(name), arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := filterOptionsLookup[name]; ok {
+		return o.new(values)
+	}
+
+	return filt, nil
+}
+
+// RegisterOptions registers a fuzzing filter instance function with the given name and declared options.
+// The filter is also registered as a fuzzing filter instance with the default values of its options.
+func RegisterOptions(name string, options []option.Option, new NewWithOptionsFunc) {
+	if new == nil {
+		panic("register fuzzing filter is nil")
+	}
+
+	values, err := option.Parse(options, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	filt, err := new(values)
+	if err != nil {
+		panic(err)
+	}
+
+	Register(name, filt)
+
+	filterOptionsLookup[name] = filterOptions{
+		options: options,
+		new:     new,
+	}
+}
+
+// walkInternal calls walk for the given token and its internal descendants as long as walk returns true
+func walkInternal(tok token.Token, walk func(tok token.Token) bool) {
+	visited := make(map[token.Token]struct{})
+
+	var visit func(tok token.Token)
+	visit = func(tok token.Token) {
+		if _, ok := visited[tok]; ok {
+			return
+		}
+		visited[tok] = struct{}{}
+
+		if !walk(tok) {
+			return
+		}
+
+		for _, c := range internalChildren(tok) {
+			visit(c)
+		}
+	}
+
+	visit(tok)
+}
+
 // ApplyFilters applies a set of filters onto a token.
 // Filters are applied in the order in which they are given. If multiple filters are replacing the same token, only the first replacement will be applied.
-// Filters are not applied onto filter generated tokens.
+// Filters are not applied onto filter generated tokens, which are the replacement and its direct children, and onto replaced tokens, since a replacement can wrap the token it replaces.
 func ApplyFilters(filters []Filter, root token.Token) (token.Token, error) {
+	selectedFilters := make([]SelectedFilter, len(filters))
+	for i, filt := range filters {
+		selectedFilters[i] = SelectedFilter{
+			Filter: filt,
+		}
+	}
+
+	return ApplySelectedFilters(selectedFilters, root)
+}
+
+// SelectedFilter holds a fuzzing filter which is only applied onto the tokens of the token definitions which are selected by its selectors.
+// A filter without selectors is applied onto all tokens.
+type SelectedFilter struct {
+	Filter    Filter
+	Selectors []Selector
+}
+
+// ApplySelectedFilters applies a set of selected filters onto a token.
+// The filters are applied like with ApplyFilters but every filter is only applied onto the tokens which are selected by one of its selectors. The error return argument is not nil, if a selector does not select any token definition.
+func ApplySelectedFilters(filters []SelectedFilter, root token.Token) (token.Token, error) {
+	return applyFilters(filters, root, false)
+}
+
+// ApplyFilterPipeline applies an ordered pipeline of selected filters onto a token.
+// In contrast to ApplySelectedFilters every token is passed through all filters in the order in which they are given. A filter is therefore applied onto the replacement of an earlier filter and can wrap it, e.g. to mutate the values of a boundary-value analysis. Tokens which are generated by a filter are only filtered by the filters after it and replaced tokens are not filtered again. The error return argument is not nil, if a selector does not select any token definition.
+func ApplyFilterPipeline(filters []SelectedFilter, root token.Token) (token.Token, error) {
+	return applyFilters(filters, root, true)
+}
+
+func applyFilters(filters []SelectedFilter, root token.Token, pipeline bool) (token.Token, error) {
 	type Pair struct {
 		token  token.Token
 		parent token.Token
+
+		// definitions holds the names of the token definitions the token is part of
+		definitions []string
+		// next holds the index of the first filter which can be applied onto the token
+		next int
+		// depth holds the number of nested filter replacements the token is part of
+		depth int
 	}
 
 	var known = make(map[token.Token]struct{})
+	// generated holds for every filter generated token of the pipeline the index of the first filter which can be applied onto it
+	var generated = make(map[token.Token]int)
+
+	selected := make([][]bool, len(filters))
+	for i := range filters {
+		selected[i] = make([]bool, len(filters[i].Selectors))
+	}
 
 	var queue = linkedlist.New()
 
@@ -76,25 +209,44 @@ func ApplyFilters(filters []Filter, root token.Token) (token.Token, error) {
 
 		tok := pair.token
 
+		definitions := pair.definitions
+		if s, ok := tok.(*primitives.Scope); ok && len(s.Names()) != 0 {
+			definitions = append(definitions[:len(definitions):len(definitions)], s.Names()...)
+		}
+
+		depth := pair.depth
+
 		// only apply filters if the token is not from one
 		if _, ok := known[tok]; !ok {
 			// apply filters
-			for i := range filters {
-				replacement, err := filters[i](tok)
+			for i := pair.next; i < len(filters) && depth < MaxDepth; i++ {
+				if len(filters[i].Selectors) != 0 {
+					found := false
+
+					for j, selector := range filters[i].Selectors {
+						if selector.Selects(definitions) {
+							selected[i][j] = true
+							found = true
+						}
+					}
+
+					if !found {
+						continue
+					}
+				}
+
+				replacement, err := filters[i].Filter(tok)
 				if err != nil {
-					return nil, fmt.Errorf("error in fuzzing filter %v: %s", filters[i], err)
+					return nil, fmt.Errorf("error in fuzzing filter %v: %s", filters[i].Filter, err)
 				}
 
 				// replace if there is something to replace with
 				if replacement != nil {
-					tok = replacement
-					known[tok] = struct{}{}
-
 					if pair.parent == nil {
-						root = tok
+						root = replacement
 					} else {
 						if pTok, ok := pair.parent.(token.InternalReplace); ok {
-							err := pTok.InternalReplace(pair.token, tok)
+							err := pTok.InternalReplace(tok, replacement)
 							if err != nil {
 								return nil, err
 							}
@@ -102,7 +254,41 @@ func ApplyFilters(filters []Filter, root token.Token) (token.Token, error) {
 							panic(fmt.Sprintf("Token %#v does not implement InternalReplace interface", pair.parent))
 						}
 					}
-					break // stop filtering this token and go to the next one
+
+					// the replaced token is not filtered again, since the replacement can wrap it
+					known[tok] = struct{}{}
+
+					if !pipeline {
+						known[replacement] = struct{}{}
+						for _, c := range internalChildren(replacement) {
+							known[c] = struct{}{}
+						}
+
+						tok = replacement
+						depth++
+
+						break // stop filtering this token and go to the next one
+					}
+
+					// only the generated tokens of the replacement skip the filters up to this one, the original tokens it holds are filtered by all filters
+					original := make(map[token.Token]struct{})
+					walkInternal(tok, func(t token.Token) bool {
+						original[t] = struct{}{}
+
+						return true
+					})
+					walkInternal(replacement, func(t token.Token) bool {
+						if _, ok := original[t]; ok {
+							return false
+						}
+
+						generated[t] = i + 1
+
+						return true
+					})
+
+					tok = replacement
+					depth++
 				}
 			}
 		}
@@ -113,20 +299,53 @@ func ApplyFilters(filters []Filter, root token.Token) (token.Token, error) {
 			c := t.InternalGet()
 
 			queue.Unshift(&Pair{
-				token:  c,
-				parent: tok,
+				token:       c,
+				parent:      tok,
+				definitions: definitions,
+				next:        generated[c],
+				depth:       depth,
 			})
 		case token.ListToken:
 			for i := t.InternalLen() - 1; i >= 0; i-- {
 				c, _ := t.InternalGet(i)
 
 				queue.Unshift(&Pair{
-					token:  c,
-					parent: tok,
+					token:       c,
+					parent:      tok,
+					definitions: definitions,
+					next:        generated[c],
+					depth:       depth,
 				})
 			}
 		}
 	}
 
+	for i := range filters {
+		for j, selector := range filters[i].Selectors {
+			if !selected[i][j] {
+				return nil, fmt.Errorf("selector %q does not select any token definition", selector)
+			}
+		}
+	}
+
 	return root, nil
 }
+
+// internalChildren returns the internal children of the given token
+func internalChildren(tok token.Token) []token.Token {
+	switch t := tok.(type) {
+	case token.ForwardToken:
+		if c := t.InternalGet(); c != nil {
+			return []token.Token{c}
+		}
+	case token.ListToken:
+		children := make([]token.Token, t.InternalLen())
+		for i := range children {
+			children[i], _ = t.InternalGet(i)
+		}
+
+		return children
+	}
+
+	return nil
+}
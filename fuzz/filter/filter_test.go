@@ -1,10 +1,12 @@
 package filter
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/zimmski/tavor/test/assert"
 
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/token"
 	"github.com/zimmski/tavor/token/lists"
 	"github.com/zimmski/tavor/token/primitives"
@@ -114,3 +116,186 @@ func TestStrategyReplaces(t *testing.T) {
 		Equal(t, "ab", rootNew.String())
 	}
 }
+
+func TestFilterOptions(t *testing.T) {
+	filt, err := NewWithOptions("PositiveBoundaryValueAnalysis", map[string]string{
+		"max": "2",
+	})
+	Nil(t, err)
+
+	replacement, err := filt(primitives.NewRangeInt(1, 100))
+	Nil(t, err)
+	Equal(t, lists.NewOne(
+		primitives.NewConstantInt(1),
+		primitives.NewConstantInt(100),
+	), replacement)
+
+	Equal(t, "max", Options("PositiveBoundaryValueAnalysis")[0].Name)
+	Nil(t, Options("NegativeBoundaryValueAnalysis"))
+
+	filt, err = NewWithOptions("PositiveBoundaryValueAnalysis", map[string]string{
+		"max": "0",
+	})
+	Nil(t, filt)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	filt, err = NewWithOptions("NegativeBoundaryValueAnalysis", map[string]string{
+		"max": "2",
+	})
+	Nil(t, filt)
+	Equal(t, option.ErrUnknownOption, err.(*option.Error).Type)
+
+	filt, err = NewWithOptions("mockachino", nil)
+	Nil(t, filt)
+	NotNil(t, err)
+}
+
+func NewMockWrapFilter(suffix string) Filter {
+	return func(tok token.Token) (token.Token, error) {
+		if t, ok := tok.(*primitives.ConstantString); ok {
+			return lists.NewAll(primitives.NewConstantString(t.String() + suffix)), nil
+		}
+
+		return nil, nil
+	}
+}
+
+func TestFilterPipeline(t *testing.T) {
+	// later filters are applied onto the replacements of earlier filters
+	{
+		filters := []SelectedFilter{
+			{Filter: NewMockReplaceFilter("b")},
+			{Filter: NewMockReplaceFilter("c")},
+		}
+		root := lists.NewAll(
+			primitives.NewConstantString("a"),
+			primitives.NewConstantString("1"),
+		)
+
+		rootNew, err := ApplyFilterPipeline(filters, root)
+		Nil(t, err)
+		Equal(t, "abc1bc", rootNew.String())
+	}
+	// later filters are applied onto tokens generated by earlier filters but not the other way around
+	{
+		filters := []SelectedFilter{
+			{Filter: NewMockReplaceFilter("c")},
+			{Filter: NewMockWrapFilter("b")},
+		}
+		root := primitives.NewConstantString("a")
+
+		rootNew, err := ApplyFilterPipeline(filters, root)
+		Nil(t, err)
+		Equal(t, "acb", rootNew.String())
+	}
+	// original tokens which are wrapped by a later filter are still filtered by earlier filters
+	{
+		filters := []SelectedFilter{
+			{Filter: NewMockReplaceFilter("c")},
+			{Filter: func(tok token.Token) (token.Token, error) {
+				if _, ok := tok.(*lists.All); ok {
+					return lists.NewOne(tok, lists.NewAll(primitives.NewConstantString("x"))), nil
+				}
+
+				return nil, nil
+			}},
+		}
+		root := lists.NewAll(primitives.NewConstantString("a"))
+
+		rootNew, err := ApplyFilterPipeline(filters, root)
+		Nil(t, err)
+		Equal(t, 2, rootNew.PermutationsAll())
+
+		Nil(t, rootNew.Permutation(0))
+		Equal(t, "ac", rootNew.String())
+		Nil(t, rootNew.Permutation(1))
+		Equal(t, "x", rootNew.String())
+	}
+	// only selected tokens are filtered
+	{
+		root := lists.NewAll(
+			primitives.NewNamedScope("A", primitives.NewConstantString("a")),
+			primitives.NewNamedScope("B", primitives.NewConstantString("b")),
+		)
+
+		selector, err := NewSelector("B")
+		Nil(t, err)
+
+		filters := []SelectedFilter{
+			{Filter: NewMockReplaceFilter("1")},
+			{Filter: NewMockReplaceFilter("2"), Selectors: []Selector{selector}},
+		}
+
+		rootNew, err := ApplyFilterPipeline(filters, root)
+		Nil(t, err)
+		Equal(t, "a1b12", rootNew.String())
+	}
+}
+
+func TestFilterWrapsReplacedToken(t *testing.T) {
+	filters := []Filter{
+		func(tok token.Token) (token.Token, error) {
+			if t, ok := tok.(*primitives.ConstantString); ok {
+				return lists.NewOne(tok, primitives.NewConstantString(t.String()+"b")), nil
+			}
+
+			return nil, nil
+		},
+	}
+
+	// neither the wrapped token nor the generated tokens are filtered again
+	root := lists.NewAll(primitives.NewConstantString("a"))
+
+	rootNew, err := ApplyFilters(filters, root)
+	Nil(t, err)
+	Equal(t, 2, rootNew.PermutationsAll())
+
+	c, _ := rootNew.(*lists.All).InternalGet(0)
+	one := c.(*lists.One)
+	Equal(t, 2, one.InternalLen())
+	Nil(t, one.Permutation(1))
+	Equal(t, "ab", rootNew.String())
+
+	// the direct children of a replacement are not filtered again
+	rootNew, err = ApplyFilters([]Filter{NewMockWrapFilter("b")}, primitives.NewConstantString("a"))
+	Nil(t, err)
+	Equal(t, "ab", rootNew.String())
+}
+
+func TestFilterMaxDepth(t *testing.T) {
+	// a filter which filters its own generated tokens again is stopped
+	{
+		nestedWrap := func(tok token.Token) (token.Token, error) {
+			if t, ok := tok.(*primitives.ConstantString); ok {
+				return lists.NewAll(lists.NewAll(primitives.NewConstantString(t.String() + "b"))), nil
+			}
+
+			return nil, nil
+		}
+
+		root := primitives.NewConstantString("a")
+
+		rootNew, err := ApplyFilters([]Filter{nestedWrap}, root)
+		Nil(t, err)
+		Equal(t, "a"+strings.Repeat("b", MaxDepth), rootNew.String())
+	}
+	// a pipeline is stopped
+	{
+		maxDepth := MaxDepth
+		MaxDepth = 2
+		defer func() {
+			MaxDepth = maxDepth
+		}()
+
+		filters := []SelectedFilter{
+			{Filter: NewMockReplaceFilter("b")},
+			{Filter: NewMockReplaceFilter("c")},
+			{Filter: NewMockReplaceFilter("d")},
+		}
+		root := primitives.NewConstantString("a")
+
+		rootNew, err := ApplyFilterPipeline(filters, root)
+		Nil(t, err)
+		Equal(t, "abc", rootNew.String())
+	}
+}
@@ -0,0 +1,126 @@
+package filter
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func init() {
+	Register("IntegerOverflow", NewIntegerOverflow())
+}
+
+// integerOverflowBits holds the bit widths of the machine representations of integers
+var integerOverflowBits = []uint{7, 8, 15, 16, 31, 32, 63, 64}
+
+// integerOverflowValues returns the edge values of the machine representations of integers
+func integerOverflowValues() []string {
+	values := []string{"-1", "0"}
+
+	one := big.NewInt(1)
+
+	for _, n := range integerOverflowBits {
+		p := new(big.Int).Lsh(one, n)
+
+		values = append(
+			values,
+			new(big.Int).Sub(p, one).String(),
+			p.String(),
+			new(big.Int).Add(p, one).String(),
+		)
+
+		// minimum of the signed representation with n+1 bits and just below it
+		min := new(big.Int).Neg(p)
+
+		values = append(
+			values,
+			min.String(),
+			new(big.Int).Sub(min, one).String(),
+		)
+	}
+
+	return values
+}
+
+// integerRepresentations returns textual variants of the given integer
+func integerRepresentations(i int) []string {
+	sign, abs := "", strconv.FormatUint(uint64(i), 10)
+	if i < 0 {
+		sign, abs = "-", strconv.FormatUint(uint64(-(i+1))+1, 10)
+	}
+
+	representations := []string{
+		sign + "00" + abs,
+	}
+
+	if i >= 0 {
+		representations = append(representations, "+"+abs)
+	}
+
+	// exponent notation
+	mantissa, exponent := abs, 0
+	for len(mantissa) > 1 && mantissa[len(mantissa)-1] == '0' {
+		mantissa = mantissa[:len(mantissa)-1]
+		exponent++
+	}
+	representations = append(representations, fmt.Sprintf("%s%se%d", sign, mantissa, exponent))
+
+	// hexadecimal notation
+	hex, _ := new(big.Int).SetString(abs, 10)
+	representations = append(representations, fmt.Sprintf("%s0x%x", sign, hex))
+
+	return representations
+}
+
+// NewIntegerOverflow returns a fuzzing filter which injects edge values of machine representations of integers.
+// This filter searches the token graph for constant integer and integer range tokens which will be transformed to a One token holding the original token as first alternative followed by the values 2^n-1, 2^n and 2^n+1 as well as -2^n and -2^n-1 for every n in 7, 8, 15, 16, 31, 32, 63 and 64, the values -1 and 0, and textual variants of the integer, or the boundaries of the range, with leading zeros, an explicit plus sign, exponent notation and hexadecimal notation. For instance, the integer 1000 has the textual variants 001000, +1000, 1e3 and 0x3e8. Values which cannot be represented by the int type of Go are generated as constant strings. In contrast to the NegativeBoundaryValueAnalysis filter, the generated values are not bound to the range of the token but to the machine representation of integers, which can be used for example to test for integer overflows.
+func NewIntegerOverflow() Filter {
+	edges := integerOverflowValues()
+
+	return func(tok token.Token) (token.Token, error) {
+		var values []string
+
+		switch t := tok.(type) {
+		case *primitives.ConstantInt:
+			values = append(values, integerRepresentations(t.Value())...)
+		case *primitives.RangeInt:
+			values = append(values, integerRepresentations(t.From())...)
+			if t.To() != t.From() {
+				values = append(values, integerRepresentations(t.To())...)
+			}
+		default:
+			return nil, nil
+		}
+
+		values = append(values, edges...)
+
+		replacements := []token.Token{tok}
+
+		seen := make(map[string]struct{})
+		if _, ok := tok.(*primitives.ConstantInt); ok {
+			seen[tok.String()] = struct{}{}
+		}
+
+		for _, v := range values {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+
+			var c token.Token
+			if i, err := strconv.Atoi(v); err == nil && strconv.Itoa(i) == v {
+				c = primitives.NewConstantInt(i)
+			} else {
+				c = primitives.NewConstantString(v)
+			}
+
+			replacements = append(replacements, c)
+		}
+
+		return lists.NewOne(replacements...), nil
+	}
+}
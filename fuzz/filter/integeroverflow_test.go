@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestIntegerRepresentations(t *testing.T) {
+	Equal(t, []string{"001000", "+1000", "1e3", "0x3e8"}, integerRepresentations(1000))
+	Equal(t, []string{"005", "+5", "5e0", "0x5"}, integerRepresentations(5))
+	Equal(t, []string{"-0012", "-12e0", "-0xc"}, integerRepresentations(-12))
+	Equal(t, []string{"000", "+0", "0e0", "0x0"}, integerRepresentations(0))
+}
+
+func TestIntegerOverflowFilter(t *testing.T) {
+	values := func(tok token.Token) []string {
+		var vs []string
+
+		one := tok.(*lists.One)
+		for i := 0; i < one.InternalLen(); i++ {
+			c, _ := one.InternalGet(i)
+
+			vs = append(vs, c.String())
+		}
+
+		return vs
+	}
+
+	filt := NewIntegerOverflow()
+
+	// constant integer
+	{
+		root := primitives.NewConstantInt(127)
+		replacement, err := filt(root)
+		Nil(t, err)
+
+		c, _ := replacement.(*lists.One).InternalGet(0)
+		True(t, Exactly(t, root, c))
+
+		vs := values(replacement)
+		Equal(t, []string{"127", "00127", "+127", "127e0", "0x7f", "-1", "0", "128", "129", "-128", "-129", "255", "256"}, vs[:13])
+		Contains(t, vs, "9223372036854775807")
+		Contains(t, vs, "-9223372036854775808")
+		Contains(t, vs, "18446744073709551617")
+		Contains(t, vs, "-18446744073709551617")
+
+		// every value is unique
+		unique := make(map[string]struct{})
+		for _, v := range vs {
+			unique[v] = struct{}{}
+		}
+		Equal(t, len(vs), len(unique))
+
+		// values which fit into an int are still integers
+		c, _ = replacement.(*lists.One).InternalGet(7)
+		Equal(t, primitives.NewConstantInt(128), c)
+		c, _ = replacement.(*lists.One).InternalGet(len(vs) - 1)
+		Equal(t, primitives.NewConstantString("-18446744073709551617"), c)
+	}
+	// integer range
+	{
+		root := primitives.NewRangeInt(1, 100)
+		replacement, err := filt(root)
+		Nil(t, err)
+
+		vs := values(replacement)
+		Equal(t, []string{"1", "001", "+1", "1e0", "0x1", "00100", "+100", "1e2", "0x64", "-1", "0"}, vs[:11])
+	}
+	// other tokens are not filtered
+	{
+		replacement, err := filt(primitives.NewConstantString("1"))
+		Nil(t, err)
+		Nil(t, replacement)
+	}
+	// generated tokens are not filtered again
+	{
+		root := lists.NewAll(
+			primitives.NewConstantInt(1),
+		)
+
+		rootNew, err := ApplyFilters([]Filter{NewIntegerOverflow()}, root)
+		Nil(t, err)
+
+		c, _ := rootNew.(*lists.All).InternalGet(0)
+		Equal(t, len(values(c)), int(rootNew.PermutationsAll()))
+		Equal(t, "1", rootNew.String())
+	}
+}
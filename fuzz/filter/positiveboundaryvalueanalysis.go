@@ -1,103 +1,169 @@
 package filter
 
 import (
+	"sort"
 	"strconv"
 
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/token"
 	"github.com/zimmski/tavor/token/lists"
 	"github.com/zimmski/tavor/token/primitives"
 )
 
 func init() {
-	Register("PositiveBoundaryValueAnalysis", NewPositiveBoundaryValueAnalysis)
+	RegisterOptions("PositiveBoundaryValueAnalysis", []option.Option{
+		{
+			Name:        "max",
+			Type:        option.Int,
+			Default:     "5",
+			Description: "The maximum number of values of a range",
+		},
+	}, func(values option.Values) (Filter, error) {
+		max := values.Int("max")
+		if max < 1 {
+			return nil, &option.Error{
+				Message: "option max must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewPositiveBoundaryValueAnalysisMax(max), nil
+	})
 }
 
 // NewPositiveBoundaryValueAnalysis implements a fuzzing filter for positive boundary-value analysis.
 // This filter searches the token graph for range tokens which will be transformed to a most 5 values: the lower and high boundaries as well as the middle values of the range. Using this filter reduces for example integer ranges of 1-100 to the integers 1, 50 and 100, which reduces permutations dramatically. A range of 1-2 will be reduces to the integers 1 and 2. A range of 1 will be reduced to the integer 1. Resulting integers of this filter therefore do not overlap. As a special case, integer ranges where the signs of the two boundaries are different are reduced to a maximum of 5 non-overlapping values. For instance, the integer range [-5, 10] is reduced to the integers -5, -1, 0, 1 and 10.
 func NewPositiveBoundaryValueAnalysis(tok token.Token) (token.Token, error) {
-	var replacements []token.Token
+	return NewPositiveBoundaryValueAnalysisMax(5)(tok)
+}
 
-	switch tok := tok.(type) {
-	case *primitives.CharacterClass:
-		l := tok.Permutations()
+// NewPositiveBoundaryValueAnalysisMax returns a fuzzing filter for positive boundary-value analysis which transforms range tokens to at most the given number of values.
+// The values are chosen like in the PositiveBoundaryValueAnalysis filter. If the maximum is lower than the number of these values, the boundaries are preferred over the middle values. If the maximum is greater than 5, the largest gaps between the values are split by additional values of the range until the maximum is reached or all values of the range are used. For instance, the integer range [0, 100] is reduced with a maximum of 7 to the integers 0, 12, 25, 37, 50, 75 and 100.
+func NewPositiveBoundaryValueAnalysisMax(max int) Filter {
+	if max < 1 {
+		panic("maximum number of values must be at least 1")
+	}
 
-		// lower boundary
-		if err := tok.Permutation(0); err != nil {
-			panic(err)
-		}
+	return func(tok token.Token) (token.Token, error) {
+		var replacements []token.Token
 
-		replacements = append(replacements, primitives.NewConstantString(tok.String()))
+		switch tok := tok.(type) {
+		case *primitives.CharacterClass:
+			l := tok.Permutations()
 
-		// middle
-		if l > 2 {
-			if err := tok.Permutation(l / 2); err != nil {
-				panic(err)
+			// the permutations are the values
+			value := func(i uint) int {
+				return int(i)
 			}
 
-			replacements = append(replacements, primitives.NewConstantString(tok.String()))
-		}
+			values := []int{0, int(l) - 1}
+			if l > 2 {
+				values = append(values, int(l/2))
+			}
+
+			for _, i := range positiveBoundaryValues(values, l, value, max) {
+				if err := tok.Permutation(uint(i)); err != nil {
+					panic(err)
+				}
 
-		// upper boundary
-		if l > 1 {
-			if err := tok.Permutation(l - 1); err != nil {
-				panic(err)
+				replacements = append(replacements, primitives.NewConstantString(tok.String()))
 			}
+		case *primitives.RangeInt:
+			l := tok.Permutations()
+
+			value := func(i uint) int {
+				if err := tok.Permutation(i); err != nil {
+					panic(err)
+				}
 
-			replacements = append(replacements, primitives.NewConstantString(tok.String()))
+				v, _ := strconv.Atoi(tok.String())
+
+				return v
+			}
+
+			// lower and upper boundary
+			values := []int{value(0), value(l - 1)}
+
+			// middle
+			if l > 2 {
+				if tok.From() < 0 && tok.To() > 0 {
+					// the boundaries are 0, -1 and 1
+					values = append(values, 0, -1, 1)
+				} else {
+					// the boundary is just the middle value
+					values = append(values, value(l/2))
+				}
+			}
+
+			for _, v := range positiveBoundaryValues(values, l, value, max) {
+				replacements = append(replacements, primitives.NewConstantInt(v))
+			}
+		default:
+			return nil, nil
 		}
-	case *primitives.RangeInt:
-		l := tok.Permutations()
 
-		// lower boundary
-		if err := tok.Permutation(0); err != nil {
-			panic(err)
+		if len(replacements) == 1 {
+			return replacements[0], nil
 		}
+		return lists.NewOne(replacements...), nil
+	}
+}
 
-		i, _ := strconv.Atoi(tok.String())
+// positiveBoundaryValues returns at most max of the given values in ascending order. The values are given in the order of their priority.
+// If max is greater than 5, the largest gaps between the values are split by the values of the permutations of the range.
+func positiveBoundaryValues(values []int, permutations uint, value func(i uint) int, max int) []int {
+	var result []int
 
-		replacements = append(replacements, primitives.NewConstantInt(i))
+	known := make(map[int]struct{})
+	for _, v := range values {
+		if len(result) == max {
+			break
+		}
 
-		// middle
-		if l > 2 {
-			if tok.From() < 0 && tok.To() > 0 {
-				// the boundaries are -1, 0 and 1
-				if tok.From() < -1 {
-					replacements = append(replacements, primitives.NewConstantInt(-1))
-				}
+		if _, ok := known[v]; ok {
+			continue
+		}
+		known[v] = struct{}{}
 
-				replacements = append(replacements, primitives.NewConstantInt(0))
+		result = append(result, v)
+	}
 
-				if tok.To() > 1 {
-					replacements = append(replacements, primitives.NewConstantInt(1))
-				}
-			} else {
-				// the boundary is just the middle value
-				if err := tok.Permutation(l / 2); err != nil {
-					panic(err)
-				}
+	sort.Ints(result)
+
+	for max > 5 && len(result) < max {
+		gap := -1
+		var gapValue int
 
-				i, _ := strconv.Atoi(tok.String())
+		for i := 0; i+1 < len(result); i++ {
+			a, b := result[i], result[i+1]
 
-				replacements = append(replacements, primitives.NewConstantInt(i))
+			if gap != -1 && b-a <= result[gap+1]-result[gap] {
+				continue
 			}
-		}
 
-		// upper boundary
-		if l > 1 {
-			if err := tok.Permutation(l - 1); err != nil {
-				panic(err)
+			// search the permutations which are within the gap
+			first := uint(sort.Search(int(permutations), func(i int) bool {
+				return value(uint(i)) > a
+			}))
+			last := uint(sort.Search(int(permutations), func(i int) bool {
+				return value(uint(i)) >= b
+			}))
+			if first >= last {
+				continue
 			}
 
-			i, _ := strconv.Atoi(tok.String())
+			gap = i
+			gapValue = value((first + last - 1) / 2)
+		}
 
-			replacements = append(replacements, primitives.NewConstantInt(i))
+		if gap == -1 {
+			break
 		}
-	default:
-		return nil, nil
-	}
 
-	if len(replacements) == 1 {
-		return replacements[0], nil
+		result = append(result, 0)
+		copy(result[gap+2:], result[gap+1:])
+		result[gap+1] = gapValue
 	}
-	return lists.NewOne(replacements...), nil
+
+	return result
 }
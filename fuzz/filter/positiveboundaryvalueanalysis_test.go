@@ -96,3 +96,60 @@ func TestNewPositiveBoundaryValueAnalysisFilter(t *testing.T) {
 		))
 	}
 }
+
+func TestNewPositiveBoundaryValueAnalysisMaxFilter(t *testing.T) {
+	// only the boundaries
+	{
+		root := primitives.NewRangeInt(-5, 10)
+		replacements, err := NewPositiveBoundaryValueAnalysisMax(3)(root)
+		Nil(t, err)
+		Equal(t, replacements, lists.NewOne(
+			primitives.NewConstantInt(-5),
+			primitives.NewConstantInt(0),
+			primitives.NewConstantInt(10),
+		))
+	}
+	// split the gaps
+	{
+		root := primitives.NewRangeInt(0, 100)
+		replacements, err := NewPositiveBoundaryValueAnalysisMax(7)(root)
+		Nil(t, err)
+		Equal(t, replacements, lists.NewOne(
+			primitives.NewConstantInt(0),
+			primitives.NewConstantInt(12),
+			primitives.NewConstantInt(25),
+			primitives.NewConstantInt(37),
+			primitives.NewConstantInt(50),
+			primitives.NewConstantInt(75),
+			primitives.NewConstantInt(100),
+		))
+	}
+	// all values of the range
+	{
+		root := primitives.NewRangeIntWithStep(10, 20, 2)
+		replacements, err := NewPositiveBoundaryValueAnalysisMax(9)(root)
+		Nil(t, err)
+		Equal(t, replacements, lists.NewOne(
+			primitives.NewConstantInt(10),
+			primitives.NewConstantInt(12),
+			primitives.NewConstantInt(14),
+			primitives.NewConstantInt(16),
+			primitives.NewConstantInt(18),
+			primitives.NewConstantInt(20),
+		))
+	}
+	// CharacterClass
+	{
+		root := primitives.NewCharacterClass("a-z")
+		replacements, err := NewPositiveBoundaryValueAnalysisMax(6)(root)
+		Nil(t, err)
+		Equal(t, replacements, lists.NewOne(
+			primitives.NewConstantString("a"),
+			primitives.NewConstantString("g"),
+			primitives.NewConstantString("j"),
+			primitives.NewConstantString("n"),
+			primitives.NewConstantString("t"),
+			primitives.NewConstantString("z"),
+		))
+	}
+}
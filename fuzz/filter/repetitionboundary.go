@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+)
+
+func init() {
+	RegisterOptions("RepetitionBoundary", []option.Option{
+		{
+			Name:        "huge",
+			Type:        option.Int,
+			Default:     "100",
+			Description: "The huge count of repetitions which is added to every repeat. Every repetition multiplies the permutations of the repeated token, so big counts make strategies which enumerate permutations like AllPermutations practically endless",
+		},
+	}, func(values option.Values) (Filter, error) {
+		huge := values.Int("huge")
+		if huge < 1 {
+			return nil, &option.Error{
+				Message: "option huge must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewRepetitionBoundary(huge), nil
+	})
+}
+
+// NewRepetitionBoundary returns a fuzzing filter for the boundaries of repetitions.
+// This filter searches the token graph for repeat tokens which will be transformed to a One token holding repeats with the fixed counts min-1, min, min+1, max-1, max and max+1 of the original repeat as well as the given huge count. Negative counts are omitted. For instance, the repeat +2,5("a") is transformed to the counts 1, 2, 3, 4, 5, 6 and the huge count. The counts outside of the bounds of the repeat deliberately violate the format, which can be used for example to test the length handling of the tested program. Since every repetition multiplies the number of permutations of the repeated token, a big huge count lets the number of permutations of the graph explode.
+func NewRepetitionBoundary(huge int) Filter {
+	if huge < 1 {
+		panic("huge count must be at least 1")
+	}
+
+	return func(tok token.Token) (token.Token, error) {
+		t, ok := tok.(*lists.Repeat)
+		if !ok {
+			return nil, nil
+		}
+
+		c, _ := t.InternalGet(0)
+		from, to := t.From(), t.To()
+
+		counts := []int64{from - 1, from, from + 1, to - 1, to, to + 1, int64(huge)}
+
+		var replacements []token.Token
+		seen := make(map[int64]struct{})
+
+		for _, count := range counts {
+			if _, ok := seen[count]; ok || count < 0 {
+				continue
+			}
+			seen[count] = struct{}{}
+
+			// every repeat has its own repeated token
+			if len(replacements) != 0 {
+				c = c.Clone()
+			}
+
+			r := lists.NewRepeat(c, count, count)
+
+			replacements = append(replacements, r)
+		}
+
+		return lists.NewOne(replacements...), nil
+	}
+}
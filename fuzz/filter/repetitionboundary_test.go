@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestRepetitionBoundaryFilter(t *testing.T) {
+	counts := func(tok token.Token) []int64 {
+		var cs []int64
+
+		one := tok.(*lists.One)
+		for i := 0; i < one.InternalLen(); i++ {
+			c, _ := one.InternalGet(i)
+			r := c.(*lists.Repeat)
+
+			Equal(t, r.From(), r.To())
+
+			cs = append(cs, r.From())
+		}
+
+		return cs
+	}
+
+	filt := NewRepetitionBoundary(100)
+
+	// bounded repeat
+	{
+		root := lists.NewRepeat(primitives.NewConstantString("a"), 2, 5)
+		replacement, err := filt(root)
+		Nil(t, err)
+		Equal(t, []int64{1, 2, 3, 4, 5, 6, 100}, counts(replacement))
+
+		// the boundaries are generated
+		one := replacement.(*lists.One)
+		Nil(t, one.Permutation(0))
+		Equal(t, "a", one.String())
+		Nil(t, one.Permutation(5))
+		Equal(t, "aaaaaa", one.String())
+	}
+	// optional repeat with the same bounds
+	{
+		root := lists.NewRepeat(primitives.NewConstantString("a"), 0, 0)
+		replacement, err := filt(root)
+		Nil(t, err)
+		Equal(t, []int64{0, 1, 100}, counts(replacement))
+	}
+	// huge count inside of the bounds
+	{
+		root := lists.NewRepeat(primitives.NewConstantString("a"), 1, 200)
+		replacement, err := filt(root)
+		Nil(t, err)
+		Equal(t, []int64{0, 1, 2, 199, 200, 201, 100}, counts(replacement))
+	}
+	// other tokens are not filtered
+	{
+		replacement, err := filt(primitives.NewConstantString("a"))
+		Nil(t, err)
+		Nil(t, replacement)
+	}
+	// generated tokens are not filtered again but nested repeats are
+	{
+		root := lists.NewRepeat(lists.NewRepeat(primitives.NewConstantString("a"), 1, 1), 1, 1)
+
+		rootNew, err := ApplyFilters([]Filter{NewRepetitionBoundary(3)}, root)
+		Nil(t, err)
+		Equal(t, []int64{0, 1, 2, 3}, counts(rootNew))
+
+		c, _ := rootNew.(*lists.One).InternalGet(1)
+		c, _ = c.(*lists.Repeat).InternalGet(0)
+		Equal(t, []int64{0, 1, 2, 3}, counts(c))
+	}
+}
+
+func TestRepetitionBoundaryFilterOptions(t *testing.T) {
+	filt, err := NewWithOptions("RepetitionBoundary", map[string]string{
+		"huge": "10",
+	})
+	Nil(t, err)
+
+	replacement, err := filt(lists.NewRepeat(primitives.NewConstantString("a"), 1, 1))
+	Nil(t, err)
+	Equal(t, 4, replacement.Permutations())
+
+	filt, err = NewWithOptions("RepetitionBoundary", map[string]string{
+		"huge": "0",
+	})
+	Nil(t, filt)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+}
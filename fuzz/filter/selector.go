@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selector selects token definitions by a path of token definition names.
+// Every name of the path has to be the name of a token definition which is directly used by the token definition of the previous name. For example the selector "Header.Length" selects the token definition Length if it is used in the definition of Header.
+type Selector []string
+
+// NewSelector returns a new selector given the names of the path separated by dots.
+// The error return argument is not nil, if the path has an empty name.
+func NewSelector(path string) (Selector, error) {
+	s := Selector(strings.Split(path, "."))
+
+	for _, name := range s {
+		if name == "" {
+			return nil, fmt.Errorf("selector %q has an empty token definition name", path)
+		}
+	}
+
+	return s, nil
+}
+
+// NewSelectors returns new selectors given their paths separated by commas.
+// The error return argument is not nil, if a path has an empty name.
+func NewSelectors(paths string) ([]Selector, error) {
+	var selectors []Selector
+
+	for _, path := range strings.Split(paths, ",") {
+		s, err := NewSelector(path)
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, s)
+	}
+
+	return selectors, nil
+}
+
+// Selects returns true if a token which is part of the given token definitions is selected.
+// The token definitions are the names of the nested token definitions beginning with the outermost.
+func (s Selector) Selects(definitions []string) bool {
+DEFINITIONS:
+	for i := 0; i+len(s) <= len(definitions); i++ {
+		for j, name := range s {
+			if definitions[i+j] != name {
+				continue DEFINITIONS
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (s Selector) String() string {
+	return strings.Join(s, ".")
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+)
+
+func TestSelector(t *testing.T) {
+	s, err := NewSelector("Header.Length")
+	Nil(t, err)
+	Equal(t, Selector{"Header", "Length"}, s)
+	Equal(t, "Header.Length", s.String())
+
+	True(t, s.Selects([]string{"START", "Header", "Length"}))
+	True(t, s.Selects([]string{"START", "Header", "Length", "Digit"}))
+	False(t, s.Selects([]string{"START", "Header"}))
+	False(t, s.Selects([]string{"START", "Body", "Length"}))
+	False(t, s.Selects([]string{"START", "Header", "Field", "Length"}))
+
+	_, err = NewSelector("Header..Length")
+	NotNil(t, err)
+
+	selectors, err := NewSelectors("Header.Length,Length")
+	Nil(t, err)
+	Equal(t, []Selector{{"Header", "Length"}, {"Length"}}, selectors)
+
+	_, err = NewSelectors("")
+	NotNil(t, err)
+}
+
+func TestApplySelectedFilters(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		$Length Int = from: 1,
+			to: 9
+
+		Header = "H" Length
+		Body = "B" Length
+
+		START = Header Body
+	`))
+	Nil(t, err)
+
+	header, err := NewSelector("Header.Length")
+	Nil(t, err)
+
+	root, err = ApplySelectedFilters([]SelectedFilter{
+		{
+			Filter:    NewNegativeBoundaryValueAnalysis,
+			Selectors: []Selector{header},
+		},
+	}, root)
+	Nil(t, err)
+
+	// only the length of the header is invalid
+	Equal(t, "H0B1", root.String())
+	Equal(t, 2*9, int(root.PermutationsAll()))
+
+	// selectors which do not select anything are an error
+	unknown, err := NewSelector("Body.Header")
+	Nil(t, err)
+
+	_, err = ApplySelectedFilters([]SelectedFilter{
+		{
+			Filter:    NewNegativeBoundaryValueAnalysis,
+			Selectors: []Selector{unknown},
+		},
+	}, root)
+	NotNil(t, err)
+
+	// every name of an alias definition can be selected
+	for _, name := range []string{"START", "AnotherLength", "Length"} {
+		root, err = parser.ParseTavor(strings.NewReader(`
+			$Length Int = from: 1,
+				to: 9
+
+			AnotherLength = Length
+
+			START = AnotherLength
+		`))
+		Nil(t, err)
+
+		selector, err := NewSelector(name)
+		Nil(t, err)
+
+		root, err = ApplySelectedFilters([]SelectedFilter{
+			{
+				Filter:    NewNegativeBoundaryValueAnalysis,
+				Selectors: []Selector{selector},
+			},
+		}, root)
+		Nil(t, err)
+
+		Equal(t, "0", root.String())
+	}
+}
@@ -1,6 +1,8 @@
 package strategy
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/log"
 	"github.com/zimmski/tavor/rand"
 	"github.com/zimmski/tavor/token"
@@ -235,3 +237,57 @@ func (s *allPermutations) setPermutation(tok token.Token, permutation uint) {
 		panic(err)
 	}
 }
+
+// NewAllPermutationsRange returns a fuzzing strategy that generates the permutations of a token graph with an index beginning from "from" up to but not including "to".
+// The permutations are numbered by token.SetPermutationIndex which allows to jump directly to a permutation. A nil "to" generates all permutations beginning from "from". This makes it possible to split the generation of all permutations into independent ranges. Every iteration of the strategy generates a new permutation. The generation is deterministic.
+func NewAllPermutationsRange(from, to *big.Int) Strategy {
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		end := root.PermutationsAllBig()
+		if to != nil && to.Cmp(end) < 0 {
+			end = to
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debugf("start all permutations routine from %s to %s", from, end)
+
+			for i := new(big.Int).Set(from); i.Cmp(end) < 0; i.Add(i, big.NewInt(1)) {
+				log.Debugf("set permutation %s", i)
+
+				if err := token.SetPermutationIndex(root, i); err != nil {
+					panic(err)
+				}
+
+				token.ResetCombinedScope(root)
+				token.ResetResetTokens(root)
+				token.ResetCombinedScope(root)
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
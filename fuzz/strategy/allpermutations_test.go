@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -594,4 +595,55 @@ func validateTokenAllPermutations(t *testing.T, tok token.Token, expect []string
 
 func TestAllPermutationsStrategyLoopDetection(t *testing.T) {
 	testStrategyLoopDetection(t, NewAllPermutations)
+	testStrategyLoopDetection(t, NewAllPermutationsRange(big.NewInt(0), nil))
+}
+
+func TestAllPermutationsRangeStrategy(t *testing.T) {
+	formats := []string{
+		`
+			START = +2(?(1)?(2))
+		`,
+		`
+			A = "a" (B | C | )
+			B = "b" C
+			C = "c" A
+
+			START = A
+		`,
+		`
+			START = ("a" | "b") *([xy]) @("c" | "d")
+		`,
+	}
+
+	for _, format := range formats {
+		o, err := parser.ParseTavor(strings.NewReader(format))
+		Nil(t, err)
+
+		// the whole range is equal to all permutations
+		expect := allPermutationsStrings(t, NewAllPermutations, o)
+		got := allPermutationsStrings(t, NewAllPermutationsRange(big.NewInt(0), nil), o)
+		Equal(t, expect, got)
+
+		// sub ranges
+		got = allPermutationsStrings(t, NewAllPermutationsRange(big.NewInt(2), big.NewInt(5)), o)
+		Equal(t, expect[2:5], got)
+
+		got = allPermutationsStrings(t, NewAllPermutationsRange(big.NewInt(3), big.NewInt(1000)), o)
+		Equal(t, expect[3:], got)
+	}
+}
+
+func allPermutationsStrings(t *testing.T, strat Strategy, root token.Token) []string {
+	ch, err := strat(root, test.NewRandTest(1))
+	Nil(t, err)
+
+	var got []string
+
+	for i := range ch {
+		got = append(got, root.String())
+
+		ch <- i
+	}
+
+	return got
 }
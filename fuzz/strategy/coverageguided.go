@@ -0,0 +1,156 @@
+package strategy
+
+import (
+	"math/big"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+)
+
+func init() {
+	RegisterFeedback("CoverageGuided", NewCoverageGuided)
+}
+
+type coverageGuidedEntry struct {
+	permutation *big.Int
+	energy      int
+}
+
+type coverageGuided struct {
+	root token.Token
+
+	corpus    []coverageGuidedEntry
+	coverage  map[string]struct{}
+	bestScore float64
+}
+
+// NewCoverageGuided implements a feedback fuzzing strategy that is guided by the coverage and score of its generations.
+// Every iteration of the strategy generates a new permutation which is either completely random or a mutation of a permutation of the corpus. A generation is added to the corpus if its feedback reports coverage which was not reported before or a score which is higher than all scores before. The latest corpus entry and entries which found more new behaviour are mutated more often. Generations are mutated like in the mutation strategy. The strategy does not end on its own.
+func NewCoverageGuided(root token.Token, r rand.Rand) (chan struct{}, chan<- Feedback, error) {
+	if r == nil {
+		return nil, nil, &Error{
+			Message: "random generator is nil",
+			Type:    ErrNilRandomGenerator,
+		}
+	}
+
+	if token.LoopExists(root) {
+		return nil, nil, &Error{
+			Message: "found endless loop in graph. Cannot proceed.",
+			Type:    ErrEndlessLoopDetected,
+		}
+	}
+
+	s := &coverageGuided{
+		root: root,
+
+		coverage: make(map[string]struct{}),
+	}
+
+	continueFuzzing := make(chan struct{})
+	feedbackFuzzing := make(chan Feedback)
+
+	go func() {
+		log.Debug("start coverage guided fuzzing routine")
+
+		for {
+			s.generate(r)
+
+			log.Debug("done with fuzzing step")
+
+			// done with this fuzzing step
+			continueFuzzing <- struct{}{}
+
+			// wait until we got feedback to the current generation
+			feedback, ok := <-feedbackFuzzing
+			if !ok {
+				log.Debug("fuzzing feedback channel closed from outside")
+
+				return
+			}
+
+			s.learn(feedback)
+
+			// wait until we are allowed to continue
+			if _, ok := <-continueFuzzing; !ok {
+				log.Debug("fuzzing continue channel closed from outside")
+
+				return
+			}
+		}
+	}()
+
+	return continueFuzzing, feedbackFuzzing, nil
+}
+
+func (s *coverageGuided) generate(r rand.Rand) {
+	rnd := &random{
+		root: s.root,
+	}
+
+	// generate from scratch if there is nothing to mutate and from time to time to not get stuck
+	if len(s.corpus) == 0 || r.Intn(8) == 0 {
+		log.Debug("generate a random permutation")
+
+		rnd.fuzz(s.root, r, token.NewVariableScope())
+	} else {
+		// the latest entry found new behaviour most recently so it is mutated more often
+		e := s.corpus[len(s.corpus)-1]
+
+		if r.Intn(2) == 0 {
+			weights := make([]int, len(s.corpus))
+			for i, e := range s.corpus {
+				weights[i] = e.energy
+			}
+
+			e = s.corpus[weightedPermutation(weights, r)]
+		}
+
+		log.Debugf("mutate permutation %s", e.permutation)
+
+		if err := token.SetPermutationIndex(s.root, e.permutation); err != nil {
+			log.Panic(err)
+		}
+
+		mutate(s.root, r)
+	}
+
+	fuzzYADDA(s.root, r)
+}
+
+func (s *coverageGuided) learn(feedback Feedback) {
+	found := 0
+
+	for _, c := range feedback.Coverage {
+		if _, ok := s.coverage[c]; !ok {
+			s.coverage[c] = struct{}{}
+
+			found++
+		}
+	}
+
+	if feedback.Score > s.bestScore {
+		s.bestScore = feedback.Score
+
+		found++
+	}
+
+	if found == 0 {
+		return
+	}
+
+	i, err := token.PermutationIndex(s.root)
+	if err != nil {
+		log.Debugf("cannot add generation to corpus: %s", err)
+
+		return
+	}
+
+	log.Debugf("add permutation %s with %d new behaviours to corpus", i, found)
+
+	s.corpus = append(s.corpus, coverageGuidedEntry{
+		permutation: i,
+		energy:      found,
+	})
+}
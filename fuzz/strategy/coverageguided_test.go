@@ -0,0 +1,136 @@
+package strategy
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+)
+
+func TestCoverageGuidedStrategyNilRandomGenerator(t *testing.T) {
+	ch, feedback, err := NewCoverageGuided(nil, nil)
+	Nil(t, ch)
+	Nil(t, feedback)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+}
+
+func TestCoverageGuidedStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = +1,8("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	// the coverage of the target is the length of the common prefix with the magic value
+	magic := "cabbac"
+	coverage := func(out string) []string {
+		var c []string
+
+		for i := 0; i < len(out) && i < len(magic) && out[i] == magic[i]; i++ {
+			c = append(c, fmt.Sprintf("prefix %d", i))
+		}
+
+		return c
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	ch, feedback, err := NewCoverageGuided(root, r)
+	Nil(t, err)
+
+	found := false
+
+	for i := 0; i < 1000; i++ {
+		_, ok := <-ch
+		True(t, ok)
+
+		out := root.String()
+		if strings.HasPrefix(out, magic) {
+			found = true
+
+			break
+		}
+
+		feedback <- Feedback{
+			Coverage: coverage(out),
+		}
+		ch <- struct{}{}
+	}
+
+	close(ch)
+	close(feedback)
+
+	True(t, found)
+}
+
+func TestCoverageGuidedStrategyScore(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = +1,10("a" | "b")
+	`))
+	Nil(t, err)
+
+	r := rand.New(rand.NewSource(1))
+
+	ch, feedback, err := NewCoverageGuided(root, r)
+	Nil(t, err)
+
+	best := 0
+
+	for i := 0; i < 5000; i++ {
+		_, ok := <-ch
+		True(t, ok)
+
+		// the score is the number of "a" characters
+		score := strings.Count(root.String(), "a")
+		if score > best {
+			best = score
+		}
+
+		feedback <- Feedback{
+			Score: float64(score),
+		}
+		ch <- struct{}{}
+	}
+
+	_, ok := <-ch
+	True(t, ok)
+
+	close(ch)
+	close(feedback)
+
+	Equal(t, 10, best)
+}
+
+func TestCoverageGuidedStrategyWithoutFeedback(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = +1,8("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	strat, err := New("CoverageGuided")
+	Nil(t, err)
+
+	ch, err := strat(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, ok := <-ch
+		True(t, ok)
+
+		NotEqual(t, "", root.String())
+
+		ch <- struct{}{}
+	}
+
+	_, ok := <-ch
+	True(t, ok)
+
+	close(ch)
+}
+
+func TestCoverageGuidedStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, WithoutFeedback(NewCoverageGuided))
+}
@@ -0,0 +1,270 @@
+package strategy
+
+import (
+	"math/big"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func init() {
+	RegisterFeedbackOptions("Genetic", []option.Option{
+		{
+			Name:        "population",
+			Type:        option.Int,
+			Default:     "20",
+			Description: "The size of the population",
+		},
+	}, func(values option.Values) (FeedbackStrategy, error) {
+		population := values.Int("population")
+		if population < 2 {
+			return nil, &option.Error{
+				Message: "option population must be at least 2",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewGenetic(nil, population), nil
+	})
+}
+
+type geneticIndividual struct {
+	permutation *big.Int
+	fitness     float64
+}
+
+type genetic struct {
+	root token.Token
+
+	seeds          []*big.Int
+	populationSize int
+
+	population []geneticIndividual
+}
+
+// NewGenetic returns a feedback fuzzing strategy which evolves a population of generations of the given size.
+// The fitness of a generation is the score of its feedback. The population is initialized with the given seeds, which are permutation indices of the graph as returned by token.PermutationIndex, and filled up with random generations. Afterwards every iteration selects two parents of the population by tournament selection and generates an offspring by crossover. The crossover takes the second parent and swaps one of its subtrees with a subtree of the first parent which comes from the same token definition. Offsprings are also mutated from time to time like in the mutation strategy. An offspring replaces the individual with the lowest fitness of the population if its fitness is not lower. Every offspring is a permutation of the graph and is therefore valid. The strategy does not end on its own.
+func NewGenetic(seeds []*big.Int, populationSize int) FeedbackStrategy {
+	if populationSize < 2 {
+		panic("population size must be at least 2")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, chan<- Feedback, error) {
+		if r == nil {
+			return nil, nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &genetic{
+			root: root,
+
+			seeds:          seeds,
+			populationSize: populationSize,
+		}
+
+		continueFuzzing := make(chan struct{})
+		feedbackFuzzing := make(chan Feedback)
+
+		go func() {
+			log.Debug("start genetic fuzzing routine")
+
+			for {
+				s.generate(r)
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we got feedback to the current generation
+				feedback, ok := <-feedbackFuzzing
+				if !ok {
+					log.Debug("fuzzing feedback channel closed from outside")
+
+					return
+				}
+
+				s.learn(feedback)
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing continue channel closed from outside")
+
+					return
+				}
+			}
+		}()
+
+		return continueFuzzing, feedbackFuzzing, nil
+	}
+}
+
+func (s *genetic) generate(r rand.Rand) {
+	if len(s.population) < s.populationSize {
+		if len(s.seeds) != 0 {
+			seed := s.seeds[0]
+			s.seeds = s.seeds[1:]
+
+			log.Debugf("add seed %s to population", seed)
+
+			if err := token.SetPermutationIndex(s.root, seed); err != nil {
+				log.Panic(err)
+			}
+		} else {
+			log.Debug("add random permutation to population")
+
+			rnd := &random{
+				root: s.root,
+			}
+
+			rnd.fuzz(s.root, r, token.NewVariableScope())
+		}
+	} else {
+		a := s.selectIndividual(r)
+		b := s.selectIndividual(r)
+
+		log.Debugf("crossover permutations %s and %s", a.permutation, b.permutation)
+
+		if !crossover(s.root, a.permutation, b.permutation, r) || r.Intn(4) == 0 {
+			mutate(s.root, r)
+		}
+	}
+
+	fuzzYADDA(s.root, r)
+}
+
+// selectIndividual selects an individual of the population by a tournament of two
+func (s *genetic) selectIndividual(r rand.Rand) geneticIndividual {
+	a := s.population[r.Intn(len(s.population))]
+	b := s.population[r.Intn(len(s.population))]
+
+	if b.fitness > a.fitness {
+		return b
+	}
+
+	return a
+}
+
+func (s *genetic) learn(feedback Feedback) {
+	i, err := token.PermutationIndex(s.root)
+	if err != nil {
+		log.Debugf("cannot add generation to population: %s", err)
+
+		return
+	}
+
+	individual := geneticIndividual{
+		permutation: i,
+		fitness:     feedback.Score,
+	}
+
+	if len(s.population) < s.populationSize {
+		s.population = append(s.population, individual)
+
+		return
+	}
+
+	worst := 0
+	for j, e := range s.population {
+		if e.fitness < s.population[worst].fitness {
+			worst = j
+		}
+	}
+
+	if individual.fitness >= s.population[worst].fitness {
+		log.Debugf("replace permutation %s with fitness %f by %s with fitness %f", s.population[worst].permutation, s.population[worst].fitness, individual.permutation, individual.fitness)
+
+		s.population[worst] = individual
+	}
+}
+
+// crossover sets the graph to the permutation b and replaces one of its definition subtrees by a subtree of the permutation a which comes from the same definition.
+// The return argument is false if there is no such subtree and the graph is just set to the permutation b.
+func crossover(root token.Token, a, b *big.Int, r rand.Rand) bool {
+	if err := token.SetPermutationIndex(root, a); err != nil {
+		log.Panic(err)
+	}
+
+	donors := make(map[string][]*big.Int)
+
+	for _, s := range definitions(root) {
+		if i, err := token.PermutationIndex(s); err == nil {
+			donors[s.Name()] = append(donors[s.Name()], i)
+		}
+	}
+
+	if err := token.SetPermutationIndex(root, b); err != nil {
+		log.Panic(err)
+	}
+
+	var toks []*primitives.Scope
+	var candidates [][]*big.Int
+	for _, s := range definitions(root) {
+		if is := fittingDonors(s, donors[s.Name()]); len(is) != 0 {
+			toks = append(toks, s)
+			candidates = append(candidates, is)
+		}
+	}
+
+	if len(toks) == 0 {
+		return false
+	}
+
+	j := r.Intn(len(toks))
+	tok := toks[j]
+	is := candidates[j]
+	i := is[r.Intn(len(is))]
+
+	log.Debugf("replace (%p)%#v with permutation %s", tok, tok, i)
+
+	if err := token.SetPermutationIndex(tok, i); err != nil {
+		log.Panic(err)
+	}
+
+	return true
+}
+
+// definitions returns all scopes of the graph which are the root of a named token definition excluding the root of the graph
+func definitions(root token.Token) []*primitives.Scope {
+	var scopes []*primitives.Scope
+
+	err := token.Walk(root, func(tok token.Token) error {
+		if s, ok := tok.(*primitives.Scope); ok && s.Name() != "" && tok != root {
+			scopes = append(scopes, s)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return scopes
+}
+
+// fittingDonors returns the permutations of the given donors which can be set for the definition token.
+// Clones of a token definition can differ in their permutation count, e.g. if the definition is recursive, so a permutation of a donor does not always exist in the token.
+func fittingDonors(tok token.Token, donors []*big.Int) []*big.Int {
+	permutations := tok.PermutationsAllBig()
+
+	var is []*big.Int
+	for _, i := range donors {
+		if i.Cmp(permutations) < 0 {
+			is = append(is, i)
+		}
+	}
+
+	return is
+}
@@ -0,0 +1,162 @@
+package strategy
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token"
+)
+
+func TestGeneticStrategyNilRandomGenerator(t *testing.T) {
+	ch, feedback, err := NewGenetic(nil, 2)(nil, nil)
+	Nil(t, ch)
+	Nil(t, feedback)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+
+	Panics(t, func() {
+		NewGenetic(nil, 1)
+	})
+}
+
+func TestGeneticStrategyCrossover(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Word = +1,3("a" | "b")
+
+		START = Word "-" Word
+	`))
+	Nil(t, err)
+
+	parse := func(input string) *big.Int {
+		Nil(t, parser.ParseInternal(root, strings.NewReader(input)))
+
+		i, err := token.PermutationIndex(root)
+		Nil(t, err)
+
+		return i
+	}
+
+	a := parse("aaa-aa")
+	b := parse("b-bbb")
+
+	r := rand.New(rand.NewSource(1))
+
+	outs := make(map[string]struct{})
+
+	for i := 0; i < 100; i++ {
+		True(t, crossover(root, a, b, r))
+
+		outs[root.String()] = struct{}{}
+	}
+
+	Equal(t, map[string]struct{}{
+		"aaa-bbb": struct{}{},
+		"aa-bbb":  struct{}{},
+		"b-aaa":   struct{}{},
+		"b-aa":    struct{}{},
+	}, outs)
+	// definitions with the same structure are not crossed
+	root, err = parser.ParseTavor(strings.NewReader(`
+		Word = +1,3("a" | "b")
+		Name = +1,3("a" | "b")
+
+		START = Word "-" Name
+	`))
+	Nil(t, err)
+
+	a = parse("aaa-aa")
+	b = parse("b-bbb")
+
+	outs = make(map[string]struct{})
+
+	for i := 0; i < 100; i++ {
+		True(t, crossover(root, a, b, r))
+
+		outs[root.String()] = struct{}{}
+	}
+
+	Equal(t, map[string]struct{}{
+		"aaa-bbb": struct{}{},
+		"b-aa":    struct{}{},
+	}, outs)
+}
+
+func TestGeneticStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Word = +1,5("a" | "b")
+
+		START = Word " " Word " " Word
+	`))
+	Nil(t, err)
+
+	seed := func(input string) *big.Int {
+		Nil(t, parser.ParseInternal(root, strings.NewReader(input)))
+
+		i, err := token.PermutationIndex(root)
+		Nil(t, err)
+
+		return i
+	}
+
+	seeds := []*big.Int{
+		seed("aaaaa b b"),
+		seed("b b aaaaa"),
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	ch, feedback, err := NewGenetic(seeds, 10)(root, r)
+	Nil(t, err)
+
+	// the population starts with the seeds
+	_, ok := <-ch
+	True(t, ok)
+	Equal(t, "aaaaa b b", root.String())
+	feedback <- Feedback{}
+	ch <- struct{}{}
+
+	_, ok = <-ch
+	True(t, ok)
+	Equal(t, "b b aaaaa", root.String())
+	feedback <- Feedback{}
+	ch <- struct{}{}
+
+	best := 0
+
+	for i := 0; i < 1000; i++ {
+		_, ok := <-ch
+		True(t, ok)
+
+		out := root.String()
+
+		// every offspring is still valid
+		Nil(t, parser.ParseInternal(root.Clone(), strings.NewReader(out)))
+
+		// the fitness is the number of "a" characters
+		score := strings.Count(out, "a")
+		if score > best {
+			best = score
+		}
+
+		feedback <- Feedback{
+			Score: float64(score),
+		}
+		ch <- struct{}{}
+	}
+
+	_, ok = <-ch
+	True(t, ok)
+
+	close(ch)
+	close(feedback)
+
+	Equal(t, 15, best)
+}
+
+func TestGeneticStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, WithoutFeedback(NewGenetic(nil, 2)))
+}
@@ -0,0 +1,489 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/conditions"
+	"github.com/zimmski/tavor/token/constraints"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+// grammarCoverageMaxAttempts is the number of generations in a row which do not cover anything new before the strategy gives up
+const grammarCoverageMaxAttempts = 100
+
+func init() {
+	RegisterOptions("GrammarCoverage", []option.Option{
+		{
+			Name:        "k",
+			Type:        option.Int,
+			Default:     "1",
+			Description: "The length of the covered paths of nested token definitions",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		k := values.Int("k")
+		if k < 1 {
+			return nil, &option.Error{
+				Message: "option k must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewGrammarCoverage(k, nil), nil
+	})
+}
+
+// grammarCoverageItem is a part of the format which has to be covered
+type grammarCoverageItem struct {
+	description string
+	covered     bool
+}
+
+// grammarCoverageSite is a token of the internal structure of the graph
+type grammarCoverageSite struct {
+	tok      token.Token
+	children []string
+
+	// items holds the items which are covered by reaching the token
+	items []*grammarCoverageItem
+	// valueItems holds the items which are covered by a permutation of a choice token
+	valueItems [][]*grammarCoverageItem
+}
+
+type grammarCoverage struct {
+	root token.Token
+	k    int
+
+	items []*grammarCoverageItem
+	keys  map[string]*grammarCoverageItem
+	sites map[string]*grammarCoverageSite
+
+	potentials map[string]int
+}
+
+// NewGrammarCoverage returns a fuzzing strategy which generates permutations of a token graph until every part of the format is covered.
+// The parts are every token definition, every alternative of every lists.One token and the present and absent states of every optional token. If k is greater than one, every sequence of k nested token definitions is covered too. Every iteration chooses greedily the permutations which cover the most parts which are not yet covered. An iteration is only done if it covers something new. Token definitions are identified by their names, which means that the clones of a definition are covered together. The strategy ends when everything is covered or if it could not cover anything new for a while, for example because parts of the format are only reachable with specific if conditions. The parts which could not be covered are given to the report function. If the report function is nil, they are logged as warnings.
+func NewGrammarCoverage(k int, report func(uncovered []string)) Strategy {
+	if k < 1 {
+		panic("k must be at least 1")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &grammarCoverage{
+			root: root,
+			k:    k,
+
+			keys:  make(map[string]*grammarCoverageItem),
+			sites: make(map[string]*grammarCoverageSite),
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debug("start grammar coverage fuzzing routine")
+
+			walkPaths(s.root, grammarCoverageChildren, s.findSites(nil, "", nil))
+
+			log.Debugf("found %d items to cover", len(s.items))
+
+			for attempts := 0; attempts < grammarCoverageMaxAttempts && !s.done(); {
+				// alternate greedy and random generations to not get stuck on items which cannot be reached
+				s.generate(attempts%2 == 0, r)
+
+				fuzzYADDA(s.root, r)
+
+				if s.record() == 0 {
+					attempts++
+
+					continue
+				}
+
+				attempts = 0
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			var uncovered []string
+			for _, item := range s.items {
+				if !item.covered {
+					uncovered = append(uncovered, item.description)
+				}
+			}
+
+			log.Infof("covered %d of %d items", len(s.items)-len(uncovered), len(s.items))
+
+			if report != nil {
+				report(uncovered)
+			} else {
+				for _, u := range uncovered {
+					log.Warnf("could not cover %s", u)
+				}
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// item returns the item for the given key and creates it if it does not exist
+func (s *grammarCoverage) item(key string, description string) *grammarCoverageItem {
+	if item, ok := s.keys[key]; ok {
+		return item
+	}
+
+	item := &grammarCoverageItem{
+		description: description,
+	}
+
+	s.keys[key] = item
+	s.items = append(s.items, item)
+
+	return item
+}
+
+// findSites returns a pathWalkFunc which searches the internal structure of the graph for the items which have to be covered.
+// Every token is identified by its path. The items of a token are identified by the name of the innermost token definition and the path of the token inside the definition. The returned function is called for the children of the given parent site whose path inside its innermost definition is local and which is part of the given definitions.
+func (s *grammarCoverage) findSites(parent *grammarCoverageSite, local string, definitions []string) pathWalkFunc {
+	return func(tok token.Token, path string, i int) pathWalkFunc {
+		site := &grammarCoverageSite{
+			tok: tok,
+		}
+		s.sites[path] = site
+
+		local := local
+		if parent != nil {
+			parent.children = append(parent.children, path)
+
+			local += "." + strconv.Itoa(i)
+		}
+
+		definitions := definitions
+
+		if t, ok := tok.(*primitives.Scope); ok && t.Name() != "" {
+			// a definition which is an alias of other definitions covers all of them
+			for _, name := range t.Names() {
+				definitions = append(definitions[:len(definitions):len(definitions)], name)
+
+				site.items = append(site.items, s.item("definition "+name, "definition "+name))
+
+				if s.k > 1 && len(definitions) >= s.k {
+					ds := strings.Join(definitions[len(definitions)-s.k:], " > ")
+
+					site.items = append(site.items, s.item("path "+ds, "definition path "+ds))
+				}
+			}
+
+			local = ""
+		}
+
+		if isGrammarCoverageChoice(tok) {
+			var definition, in string
+			if len(definitions) != 0 {
+				definition = definitions[len(definitions)-1]
+				in = " in definition " + definition
+			}
+
+			site.valueItems = make([][]*grammarCoverageItem, tok.Permutations())
+
+			for v := range site.valueItems {
+				var key, description string
+
+				switch tok.(type) {
+				case *lists.One:
+					key = fmt.Sprintf("alternative %d %s%s", v, definition, local)
+					description = fmt.Sprintf("alternative %d of %s%s", v+1, describe(tok, 2), in)
+				case *constraints.Optional:
+					key = fmt.Sprintf("optional %t %s%s", v == 1, definition, local)
+					description = fmt.Sprintf("%s state of %s%s", presentOrAbsent(v == 1), describe(tok, 2), in)
+				case *lists.Repeat:
+					key = fmt.Sprintf("optional %t %s%s", v != 0, definition, local)
+					description = fmt.Sprintf("%s state of %s%s", presentOrAbsent(v != 0), describe(tok, 2), in)
+				}
+
+				site.valueItems[v] = append(site.valueItems[v], s.item(key, description))
+			}
+		}
+
+		return s.findSites(site, local, definitions)
+	}
+}
+
+func (s *grammarCoverage) done() bool {
+	for _, item := range s.items {
+		if !item.covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generate permutates the graph either greedily so that as many uncovered items as possible are covered or at random
+func (s *grammarCoverage) generate(greedy bool, r rand.Rand) {
+	s.potentials = make(map[string]int)
+
+	fuzzPath(s.root, "", func(tok token.Token, path string) (uint, bool) {
+		site, ok := s.sites[path]
+		if !ok || !greedy || site.valueItems == nil || uint(len(site.valueItems)) != tok.Permutations() {
+			return 0, false
+		}
+
+		return s.choose(site, r), true
+	}, r, token.NewVariableScope())
+}
+
+// choose returns one of the permutations of the choice token of the site which cover the most uncovered items
+func (s *grammarCoverage) choose(site *grammarCoverageSite, r rand.Rand) uint {
+	var best []uint
+	bestScore := -1
+
+	for v := range site.valueItems {
+		score := s.score(site, uint(v))
+
+		if score > bestScore {
+			best = []uint{uint(v)}
+			bestScore = score
+		} else if score == bestScore {
+			best = append(best, uint(v))
+		}
+	}
+
+	return best[r.Intn(len(best))]
+}
+
+// score returns the number of uncovered items which can be covered by the given permutation of a choice token
+func (s *grammarCoverage) score(site *grammarCoverageSite, v uint) int {
+	score := uncovered(site.valueItems[v])
+
+	for i, c := range site.children {
+		if nWiseReaches(site.tok, v, i) {
+			score += s.potential(c)
+		}
+	}
+
+	return score
+}
+
+// potential returns the number of uncovered items which can be covered by the token of the given path and its children
+func (s *grammarCoverage) potential(path string) int {
+	if p, ok := s.potentials[path]; ok {
+		return p
+	}
+
+	site := s.sites[path]
+	potential := uncovered(site.items)
+
+	switch {
+	case site.valueItems != nil:
+		best := 0
+		for v := range site.valueItems {
+			if score := s.score(site, uint(v)); score > best {
+				best = score
+			}
+		}
+
+		potential += best
+	case isGrammarCoverageUncontrollable(site.tok):
+		// the children cannot be influenced
+	default:
+		for _, c := range site.children {
+			potential += s.potential(c)
+		}
+	}
+
+	s.potentials[path] = potential
+
+	return potential
+}
+
+// record marks the items of the current permutation of the graph as covered and returns the number of newly covered items
+func (s *grammarCoverage) record() int {
+	found := 0
+
+	cover := func(items []*grammarCoverageItem) {
+		for _, item := range items {
+			if !item.covered {
+				item.covered = true
+
+				log.Debugf("covered %s", item.description)
+
+				found++
+			}
+		}
+	}
+
+	var walk func(tok token.Token, path string)
+	walk = func(tok token.Token, path string) {
+		site, ok := s.sites[path]
+		if !ok {
+			return
+		}
+
+		cover(site.items)
+
+		if site.valueItems != nil {
+			if t, ok := tok.(token.CurrentPermutation); ok {
+				if v := t.CurrentPermutation(); v < uint(len(site.valueItems)) {
+					cover(site.valueItems[v])
+				}
+			}
+		}
+
+		if t, ok := tok.(token.Follow); ok && !t.Follow() {
+			return
+		}
+
+		switch t := tok.(type) {
+		case *conditions.If:
+			for i, pair := range t.Pairs {
+				if pair.Head.Evaluate() {
+					walk(pair.Body, path+"."+strconv.Itoa(i))
+
+					break
+				}
+			}
+		case token.ForwardToken:
+			if v := t.Get(); v != nil {
+				walk(v, path+".0")
+			}
+		case token.ListToken:
+			l := t.Len()
+
+			for i := 0; i < l; i++ {
+				c, _ := t.Get(i)
+
+				walk(c, path+"."+strconv.Itoa(internalIndex(t, c, i)))
+			}
+		}
+	}
+
+	walk(s.root, "")
+
+	return found
+}
+
+// isGrammarCoverageChoice returns true if the token is a choice token whose permutations are covered
+func isGrammarCoverageChoice(tok token.Token) bool {
+	switch t := tok.(type) {
+	case *lists.One, *constraints.Optional:
+		return tok.Permutations() > 1
+	case *lists.Repeat:
+		return t.From() == 0 && tok.Permutations() > 1
+	}
+
+	return false
+}
+
+// isGrammarCoverageUncontrollable returns true if the reached children of the token cannot be influenced by permutations
+func isGrammarCoverageUncontrollable(tok token.Token) bool {
+	_, ok := tok.(*conditions.If)
+
+	return ok
+}
+
+// grammarCoverageChildren returns the internal children of the token including the bodies of if conditions
+func grammarCoverageChildren(tok token.Token) []token.Token {
+	if t, ok := tok.(*conditions.If); ok {
+		children := make([]token.Token, len(t.Pairs))
+		for i, pair := range t.Pairs {
+			children[i] = pair.Body
+		}
+
+		return children
+	}
+
+	return internalChildren(tok)
+}
+
+func uncovered(items []*grammarCoverageItem) int {
+	n := 0
+
+	for _, item := range items {
+		if !item.covered {
+			n++
+		}
+	}
+
+	return n
+}
+
+func presentOrAbsent(present bool) string {
+	if present {
+		return "present"
+	}
+
+	return "absent"
+}
+
+// describe returns a format like description of the token up to the given depth
+func describe(tok token.Token, depth int) string {
+	if depth < 0 {
+		return "..."
+	}
+
+	describeChildren := func(separator string) string {
+		var ds []string
+		for _, c := range grammarCoverageChildren(tok) {
+			ds = append(ds, describe(c, depth-1))
+		}
+
+		return strings.Join(ds, separator)
+	}
+
+	switch t := tok.(type) {
+	case *primitives.Scope, *primitives.Pointer:
+		if c := grammarCoverageChildren(tok); len(c) == 1 {
+			return describe(c[0], depth)
+		}
+	case *lists.One:
+		return "(" + describeChildren(" | ") + ")"
+	case *constraints.Optional:
+		return "?(" + describeChildren("") + ")"
+	case *lists.Repeat:
+		return fmt.Sprintf("+%d,%d(%s)", t.From(), t.To(), describeChildren(""))
+	case *lists.All:
+		return describeChildren(" ")
+	case *conditions.If:
+		return "{if ...}"
+	}
+
+	if len(grammarCoverageChildren(tok)) == 0 && tok.PermutationsAll() == 1 {
+		return strconv.Quote(tok.String())
+	}
+
+	return fmt.Sprintf("%T", tok)
+}
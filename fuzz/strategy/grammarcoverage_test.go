@@ -0,0 +1,120 @@
+package strategy
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token"
+)
+
+func grammarCoverageStrings(t *testing.T, root token.Token, k int) ([]string, []string) {
+	var uncovered []string
+	reported := false
+
+	ch, err := NewGrammarCoverage(k, func(u []string) {
+		uncovered = u
+		reported = true
+	})(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	var outs []string
+
+	for i := range ch {
+		outs = append(outs, root.String())
+
+		ch <- i
+	}
+
+	True(t, reported)
+
+	return outs, uncovered
+}
+
+func TestGrammarCoverageStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Letter = "a" | "b" | "c" | "d"
+		Digit = "1" | "2"
+
+		START = Letter ?("-" Digit) *("!")
+	`))
+	Nil(t, err)
+
+	outs, uncovered := grammarCoverageStrings(t, root, 1)
+	Nil(t, uncovered)
+
+	// every alternative needs its own generation but everything else is covered alongside
+	Equal(t, 4, len(outs))
+
+	all := strings.Join(outs, " ")
+	for _, s := range []string{"a", "b", "c", "d", "-1", "-2", "!"} {
+		True(t, strings.Contains(all, s), s)
+	}
+
+	absent := false
+	for _, out := range outs {
+		if !strings.Contains(out, "-") {
+			absent = true
+		}
+	}
+	True(t, absent)
+
+	Panics(t, func() {
+		NewGrammarCoverage(0, nil)
+	})
+}
+
+func TestGrammarCoverageStrategyPaths(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Inner = "x"
+		A = "a" ?(Inner)
+		B = "b" ?(Inner)
+
+		START = A | B
+	`))
+	Nil(t, err)
+
+	// the optional tokens of A and B are covered separately although they have the same structure
+	outs, uncovered := grammarCoverageStrings(t, root, 1)
+	Nil(t, uncovered)
+	Equal(t, 4, len(outs))
+
+	for _, out := range []string{"a", "ax", "b", "bx"} {
+		Contains(t, outs, out)
+	}
+
+	// the definition Inner has to be covered inside A and B
+	outs, uncovered = grammarCoverageStrings(t, root, 2)
+	Nil(t, uncovered)
+	Equal(t, 4, len(outs))
+
+	Contains(t, outs, "ax")
+	Contains(t, outs, "bx")
+}
+
+func TestGrammarCoverageStrategyUncovered(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = Choose<var> "->" Print
+
+		Choose = 1 | 2
+
+		Print = {if var.Value == 3} ("A" | "B") {endif}
+	`))
+	Nil(t, err)
+
+	outs, uncovered := grammarCoverageStrings(t, root, 1)
+	Equal(t, 2, len(outs))
+	True(t, len(uncovered) > 0)
+
+	for _, u := range uncovered {
+		True(t, strings.Contains(u, `("A" | "B")`), u)
+		True(t, strings.HasSuffix(u, "in definition Print"), u)
+	}
+}
+
+func TestGrammarCoverageStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewGrammarCoverage(1, nil))
+}
@@ -0,0 +1,428 @@
+package strategy
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+// invalidMaxAttempts is the number of generations in a row to which no violation can be applied before the strategy gives up
+const invalidMaxAttempts = 100
+
+func init() {
+	RegisterOptions("Invalid", []option.Option{
+		{
+			Name:        "count",
+			Type:        option.Int,
+			Default:     "100",
+			Description: "How many generations should be generated",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		if values.Int("count") < 1 {
+			return nil, &option.Error{
+				Message: "option count must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewInvalid(values.Int("count"), nil), nil
+	})
+}
+
+// invalidReplacement is a replaced child of a token
+type invalidReplacement struct {
+	parent   token.Token
+	old, new token.Token
+}
+
+// invalidToken is a token of the current generation
+type invalidToken struct {
+	tok    token.Token
+	parent *invalidToken
+	offset int
+	str    string
+
+	children []*invalidToken
+}
+
+type invalid struct {
+	root token.Token
+
+	tokens       []*invalidToken
+	replacements []invalidReplacement
+}
+
+// invalidViolation applies a violation to the current generation and returns its description. False is returned if the violation cannot be applied.
+type invalidViolation func(s *invalid, r rand.Rand) (string, bool)
+
+var invalidViolations = []invalidViolation{
+	(*invalid).drop,
+	(*invalid).duplicate,
+	(*invalid).swap,
+	(*invalid).truncate,
+	(*invalid).insert,
+}
+
+// NewInvalid returns a fuzzing strategy which generates almost valid inputs by applying exactly one violation of the format to a random generation.
+// The violations are dropping a required token, duplicating a terminal, swapping two siblings, truncating the output and inserting the output of another token definition. The violations are applied in turn. The description of the violation of every generation is given to the label function. If the label function is nil, the description is written to STDERR. Note that a violation can by chance still result in a valid input. The strategy ends after the given number of iterations.
+func NewInvalid(iterations int, label func(violation string)) Strategy {
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &invalid{
+			root: root,
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debug("start invalid fuzzing routine")
+
+			rnd := &random{
+				root: s.root,
+			}
+
+			next := 0
+
+			for i, attempts := 0, 0; i < iterations && attempts < invalidMaxAttempts; {
+				rnd.fuzz(s.root, r, token.NewVariableScope())
+
+				fuzzYADDA(s.root, r)
+
+				valid := s.root.String()
+
+				var violation string
+				applied := false
+
+				for j := 0; j < len(invalidViolations) && !applied; j++ {
+					s.collect()
+
+					violation, applied = invalidViolations[(next+j)%len(invalidViolations)](s, r)
+
+					if applied && s.root.String() == valid {
+						// the violation did not change anything
+						s.restore()
+
+						applied = false
+					}
+
+					if applied {
+						next = (next + j + 1) % len(invalidViolations)
+					}
+				}
+
+				if !applied {
+					log.Debugf("could not apply a violation to %q", valid)
+
+					attempts++
+
+					continue
+				}
+
+				attempts = 0
+				i++
+
+				if label != nil {
+					label(violation)
+				} else {
+					fmt.Fprintf(os.Stderr, "generation violates the format by %s\n", violation)
+				}
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					s.restore()
+
+					return
+				}
+
+				s.restore()
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// collect collects the tokens of the current generation with their outputs
+func (s *invalid) collect() {
+	s.tokens = nil
+
+	var walk func(tok token.Token, parent *invalidToken, offset int) *invalidToken
+	walk = func(tok token.Token, parent *invalidToken, offset int) *invalidToken {
+		t := &invalidToken{
+			tok:    tok,
+			parent: parent,
+			offset: offset,
+			str:    tok.String(),
+		}
+
+		s.tokens = append(s.tokens, t)
+
+		children := uniformChildren(tok)
+
+		// only follow children which make up the output of the token
+		var sum int
+		for _, c := range children {
+			sum += len(c.String())
+		}
+
+		if sum == len(t.str) {
+			for _, c := range children {
+				child := walk(c, t, offset)
+				t.children = append(t.children, child)
+
+				offset += len(child.str)
+			}
+		}
+
+		return t
+	}
+
+	walk(s.root, nil, 0)
+}
+
+// replaceable returns true if the token can be replaced in its parent
+func (t *invalidToken) replaceable() bool {
+	if t.parent == nil {
+		return false
+	}
+
+	if _, ok := t.parent.tok.(token.InternalReplace); !ok {
+		return false
+	}
+
+	// the child must be an internal token and not for example a clone of a repeated token
+	switch p := t.parent.tok.(type) {
+	case token.ForwardToken:
+		return p.InternalGet() == t.tok
+	case token.ListToken:
+		for i := 0; i < p.InternalLen(); i++ {
+			if c, _ := p.InternalGet(i); c == t.tok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAncestor returns true if the token is an ancestor of the given token
+func (t *invalidToken) isAncestor(o *invalidToken) bool {
+	for p := o.parent; p != nil; p = p.parent {
+		if p == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replace replaces the token in its parent with the given token
+func (s *invalid) replace(t *invalidToken, tok token.Token) {
+	if err := t.parent.tok.(token.InternalReplace).InternalReplace(t.tok, tok); err != nil {
+		log.Panic(err)
+	}
+
+	s.replacements = append(s.replacements, invalidReplacement{
+		parent: t.parent.tok,
+		old:    t.tok,
+		new:    tok,
+	})
+}
+
+// restore reverts all replacements
+func (s *invalid) restore() {
+	for i := len(s.replacements) - 1; i >= 0; i-- {
+		re := s.replacements[i]
+
+		if err := re.parent.(token.InternalReplace).InternalReplace(re.new, re.old); err != nil {
+			log.Panic(err)
+		}
+	}
+
+	s.replacements = nil
+}
+
+// pick returns a random token which fulfills the given condition
+func (s *invalid) pick(r rand.Rand, condition func(t *invalidToken) bool) (*invalidToken, bool) {
+	var ts []*invalidToken
+	for _, t := range s.tokens {
+		if t.str != "" && t.replaceable() && condition(t) {
+			ts = append(ts, t)
+		}
+	}
+
+	if len(ts) == 0 {
+		return nil, false
+	}
+
+	return ts[r.Intn(len(ts))], true
+}
+
+func isInvalidTerminal(t *invalidToken) bool {
+	return len(t.children) == 0
+}
+
+// drop drops a required token
+func (s *invalid) drop(r rand.Rand) (string, bool) {
+	t, ok := s.pick(r, func(t *invalidToken) bool {
+		_, ok := t.parent.tok.(*lists.All)
+
+		return ok
+	})
+	if !ok {
+		return "", false
+	}
+
+	s.replace(t, primitives.NewConstantString(""))
+
+	return fmt.Sprintf("dropping the required %q at %d", t.str, t.offset), true
+}
+
+// duplicate duplicates a terminal
+func (s *invalid) duplicate(r rand.Rand) (string, bool) {
+	t, ok := s.pick(r, isInvalidTerminal)
+	if !ok {
+		return "", false
+	}
+
+	s.replace(t, lists.NewAll(t.tok, primitives.NewConstantString(t.str)))
+
+	return fmt.Sprintf("duplicating the terminal %q at %d", t.str, t.offset), true
+}
+
+// swap swaps two different siblings
+func (s *invalid) swap(r rand.Rand) (string, bool) {
+	t, ok := s.pick(r, func(t *invalidToken) bool {
+		if _, ok := t.parent.tok.(*lists.All); !ok {
+			return false
+		}
+
+		for _, c := range t.parent.children {
+			if c.offset > t.offset && c.str != "" && c.str != t.str && c.replaceable() {
+				return true
+			}
+		}
+
+		return false
+	})
+	if !ok {
+		return "", false
+	}
+
+	var siblings []*invalidToken
+	for _, c := range t.parent.children {
+		if c.offset > t.offset && c.str != "" && c.str != t.str && c.replaceable() {
+			siblings = append(siblings, c)
+		}
+	}
+
+	o := siblings[r.Intn(len(siblings))]
+
+	s.replace(t, primitives.NewConstantString(o.str))
+	s.replace(o, primitives.NewConstantString(t.str))
+
+	return fmt.Sprintf("swapping the siblings %q at %d and %q at %d", t.str, t.offset, o.str, o.offset), true
+}
+
+// truncate truncates the output
+func (s *invalid) truncate(r rand.Rand) (string, bool) {
+	root := s.tokens[0]
+	if root.str == "" {
+		return "", false
+	}
+
+	n := r.Intn(len(root.str))
+
+	var cut func(t *invalidToken) bool
+	cut = func(t *invalidToken) bool {
+		end := t.offset + len(t.str)
+
+		switch {
+		case end <= n || t.str == "":
+			return true
+		case t.offset >= n && t.replaceable():
+			s.replace(t, primitives.NewConstantString(""))
+
+			return true
+		case len(t.children) == 0:
+			if !t.replaceable() {
+				return false
+			}
+
+			s.replace(t, primitives.NewConstantString(t.str[:n-t.offset]))
+
+			return true
+		}
+
+		for _, c := range t.children {
+			if !cut(c) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !cut(root) {
+		s.restore()
+
+		return "", false
+	}
+
+	return fmt.Sprintf("truncating the output after %d of %d bytes", n, len(root.str)), true
+}
+
+// insert inserts the output of another token definition after a terminal
+func (s *invalid) insert(r rand.Rand) (string, bool) {
+	t, ok := s.pick(r, isInvalidTerminal)
+	if !ok {
+		return "", false
+	}
+
+	var definitions []*invalidToken
+	for _, d := range s.tokens {
+		if _, ok := d.tok.(*primitives.Scope); ok && d.str != "" && d != s.tokens[0] && !d.isAncestor(t) {
+			definitions = append(definitions, d)
+		}
+	}
+
+	if len(definitions) == 0 {
+		return "", false
+	}
+
+	d := definitions[r.Intn(len(definitions))]
+
+	s.replace(t, lists.NewAll(t.tok, primitives.NewConstantString(d.str)))
+
+	return fmt.Sprintf("inserting %q of another definition after %q at %d", d.str, t.str, t.offset), true
+}
@@ -0,0 +1,97 @@
+package strategy
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestInvalidStrategy(t *testing.T) {
+	format := `
+		Key = "a" | "b"
+		Value = +1,3("x")
+		Pair = Key "=" Value
+
+		START = Pair ";" Pair
+	`
+
+	root, err := parser.ParseTavor(strings.NewReader(format))
+	Nil(t, err)
+
+	validator, err := parser.ParseTavor(strings.NewReader(format))
+	Nil(t, err)
+
+	var violations []string
+
+	ch, err := NewInvalid(50, func(violation string) {
+		violations = append(violations, violation)
+	})(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	invalids := 0
+
+	for i := range ch {
+		if parser.ParseInternal(validator, strings.NewReader(root.String())) != nil {
+			invalids++
+		}
+
+		ch <- i
+	}
+
+	Equal(t, 50, len(violations))
+
+	// every violation is applied
+	for _, prefix := range []string{"dropping", "duplicating", "swapping", "truncating", "inserting"} {
+		n := 0
+		for _, v := range violations {
+			if strings.HasPrefix(v, prefix) {
+				n++
+			}
+		}
+
+		True(t, n > 5, prefix)
+	}
+
+	// some violations like duplicating a repeated terminal can by chance result in valid inputs
+	True(t, invalids > 35)
+
+	// the graph is valid again after the last generation
+	Nil(t, parser.ParseInternal(validator, strings.NewReader(root.String())))
+}
+
+func TestInvalidStrategyNoViolation(t *testing.T) {
+	// nothing can be violated in an empty output
+	root := primitives.NewScope(primitives.NewConstantString(""))
+
+	ch, err := NewInvalid(10, nil)(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	_, ok := <-ch
+	False(t, ok)
+}
+
+func TestInvalidStrategyOptions(t *testing.T) {
+	for _, count := range []string{"0", "-1"} {
+		strat, err := NewWithOptions("Invalid", map[string]string{
+			"count": count,
+		})
+		Nil(t, strat)
+		Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+	}
+}
+
+func TestInvalidStrategyNilRandomGenerator(t *testing.T) {
+	ch, err := NewInvalid(1, nil)(nil, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+}
+
+func TestInvalidStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewInvalid(1, nil))
+}
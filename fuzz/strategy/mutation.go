@@ -0,0 +1,160 @@
+package strategy
+
+import (
+	"math/big"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+)
+
+// NewMutation returns a fuzzing strategy that mutates the given seeds of a token graph.
+// A seed is a permutation index of the graph as returned by token.PermutationIndex, e.g. of an input which was parsed with parser.ParseInternal. Every iteration mutates the next seed in turn while the rest of the seed is kept intact. A mutation either permutates a random subtree of the graph at random, or changes the permutation of a random token but keeps the permutations of its children. This includes for example toggling an optional token or changing the repetitions of a list token. The strategy ends after the given number of iterations.
+func NewMutation(seeds []*big.Int, iterations int) Strategy {
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if len(seeds) == 0 {
+			return nil, &Error{
+				Message: "no seeds to mutate",
+				Type:    ErrNoSeeds,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debug("start mutation fuzzing routine")
+
+			for i := 0; i < iterations; i++ {
+				seed := seeds[i%len(seeds)]
+
+				log.Debugf("mutate seed %s", seed)
+
+				if err := token.SetPermutationIndex(root, seed); err != nil {
+					log.Panic(err)
+				}
+
+				mutate(root, r)
+
+				fuzzYADDA(root, r)
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// mutate mutates one random token of the graph
+func mutate(root token.Token, r rand.Rand) {
+	var toks []token.Token
+
+	err := token.Walk(root, func(tok token.Token) error {
+		if tok.Permutations() > 1 || hasUniformChildren(tok) {
+			toks = append(toks, tok)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if len(toks) == 0 {
+		return
+	}
+
+	tok := toks[r.Intn(len(toks))]
+
+	if r.Intn(2) == 0 {
+		log.Debugf("permutate subtree of (%p)%#v", tok, tok)
+
+		rnd := &random{
+			root: root,
+		}
+
+		rnd.fuzz(tok, r, token.NewVariableScope())
+	} else {
+		mutateToken(tok, r)
+	}
+}
+
+// mutateToken changes the permutation of the given token at random but keeps the permutations of its children if possible
+func mutateToken(tok token.Token, r rand.Rand) {
+	log.Debugf("mutate (%p)%#v", tok, tok)
+
+	old := uniformChildren(tok)
+	oldPermutations := make([]*big.Int, len(old))
+	oldCounts := make([]*big.Int, len(old))
+
+	for i, c := range old {
+		if p, err := token.PermutationIndex(c); err == nil {
+			oldPermutations[i] = p
+			oldCounts[i] = c.PermutationsAllBig()
+		}
+	}
+
+	p := randomPermutation(tok, r)
+
+	// choose a different permutation if the current one is known
+	if t, ok := tok.(token.CurrentPermutation); ok && tok.Permutations() > 1 {
+		p = uint(r.Int63n(int64(tok.Permutations() - 1)))
+
+		if p >= t.CurrentPermutation() {
+			p++
+		}
+	}
+
+	if err := tok.Permutation(p); err != nil {
+		log.Panic(err)
+	}
+
+	rnd := &random{
+		root: tok,
+	}
+
+	for i, c := range uniformChildren(tok) {
+		if i < len(old) {
+			if c == old[i] {
+				continue
+			}
+
+			// the child was regenerated so try to restore the permutation of the former child
+			if oldPermutations[i] != nil && c.PermutationsAllBig().Cmp(oldCounts[i]) == 0 {
+				if err := token.SetPermutationIndex(c, oldPermutations[i]); err == nil {
+					continue
+				}
+			}
+		}
+
+		rnd.fuzz(c, r, token.NewVariableScope())
+	}
+}
@@ -0,0 +1,74 @@
+package strategy
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token"
+)
+
+func TestMutationStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Item = "<" ("x" | "y" | "z") ?("!") ">"
+
+		START = +1,5(Item)
+	`))
+	Nil(t, err)
+
+	r := rand.New(rand.NewSource(1))
+
+	ch, err := NewMutation(nil, 10)(root, r)
+	Nil(t, ch)
+	Equal(t, ErrNoSeeds, err.(*Error).Type)
+
+	ch, err = NewMutation([]*big.Int{big.NewInt(0)}, 10)(root, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+
+	var seeds []*big.Int
+	for _, input := range []string{"<x><y!><z><x!>", "<z!><z!><z!><z!><z!>"} {
+		errs := parser.ParseInternal(root, strings.NewReader(input))
+		Nil(t, errs)
+
+		seed, err := token.PermutationIndex(root)
+		Nil(t, err)
+		Equal(t, input, root.String())
+
+		seeds = append(seeds, seed)
+	}
+
+	ch, err = NewMutation(seeds, 200)(root, r)
+	Nil(t, err)
+
+	iterations := 0
+	similar := 0
+
+	for i := range ch {
+		out := root.String()
+		seed := []string{"<x><y!><z><x!>", "<z!><z!><z!><z!><z!>"}[iterations%2]
+
+		// every mutation is still valid
+		Nil(t, parser.ParseInternal(root.Clone(), strings.NewReader(out)))
+
+		// most mutations keep the beginning or the end of the seed intact
+		if len(out) > 4 && (out[:4] == seed[:4] || out[len(out)-4:] == seed[len(seed)-4:]) {
+			similar++
+		}
+
+		iterations++
+
+		ch <- i
+	}
+
+	Equal(t, 200, iterations)
+	True(t, similar > 150)
+}
+
+func TestMutationStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewMutation([]*big.Int{big.NewInt(0)}, 1))
+}
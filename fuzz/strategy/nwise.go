@@ -0,0 +1,427 @@
+package strategy
+
+import (
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/constraints"
+	"github.com/zimmski/tavor/token/lists"
+)
+
+func init() {
+	RegisterOptions("NWise", []option.Option{
+		{
+			Name:        "n",
+			Type:        option.Int,
+			Default:     "2",
+			Description: "How many choices should be combined",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		n := values.Int("n")
+		if n < 1 {
+			return nil, &option.Error{
+				Message: "option n must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewNWise(n), nil
+	})
+}
+
+// nWiseRequirement restricts a choice point to the given values
+type nWiseRequirement struct {
+	point  int
+	values []uint
+}
+
+// nWiseChoice holds a choice point of the token graph
+type nWiseChoice struct {
+	permutations uint
+	// requirements holds the choices of the ancestors which are needed to reach the choice point
+	requirements []nWiseRequirement
+}
+
+// nWiseValue is a value of a choice point
+type nWiseValue struct {
+	point int
+	value uint
+}
+
+// nWiseTuple is a combination of values of distinct choice points
+type nWiseTuple struct {
+	values  []nWiseValue
+	covered bool
+}
+
+type nWise struct {
+	root token.Token
+
+	choices []nWiseChoice
+	points  map[string]int
+
+	tuples []*nWiseTuple
+	// tuplesOf holds for every value of every choice point the tuples which contain the value
+	tuplesOf [][][]*nWiseTuple
+}
+
+// NewNWise returns a fuzzing strategy which generates a covering array over the choice points of a token graph.
+// Choice points are the alternatives of lists.One tokens, the states of constraints.Optional tokens and the repetitions of lists.Repeat tokens. Every combination of values of any n choice points which can be reached together appears in at least one generation. Choice points which are reached more than once in a generation, e.g. inside a repeated token, get the same value. Every iteration greedily chooses the values of the choice points so that as many uncovered combinations as possible are covered. All other tokens are permutated at random. The strategy ends when all combinations are covered.
+func NewNWise(n int) Strategy {
+	if n < 1 {
+		panic("n must be at least 1")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &nWise{
+			root: root,
+
+			points: make(map[string]int),
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debug("start n-wise fuzzing routine")
+
+			walkPaths(s.root, internalChildren, s.findChoices(-1, nil, nil))
+			s.combine(n)
+
+			log.Debugf("found %d choice points and %d combinations", len(s.choices), len(s.tuples))
+
+			for _, t := range s.tuples {
+				if t.covered {
+					continue
+				}
+
+				s.generate(t, r)
+
+				fuzzYADDA(s.root, r)
+
+				log.Debug("done with fuzzing step")
+
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			if len(s.tuples) == 0 {
+				rnd := &random{
+					root: s.root,
+				}
+
+				rnd.fuzz(s.root, r, token.NewVariableScope())
+
+				fuzzYADDA(s.root, r)
+
+				log.Debug("done with fuzzing step")
+
+				// done with the last fuzzing step
+				continueFuzzing <- struct{}{}
+
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// findChoices returns a pathWalkFunc which searches the internal structure of the graph for choice points.
+// Every choice point is identified by its path. The returned function is called for the children of the given parent token which is the choice point with the given index, or -1 if the parent is no choice point.
+func (s *nWise) findChoices(point int, parent token.Token, requirements []nWiseRequirement) pathWalkFunc {
+	return func(tok token.Token, path string, i int) pathWalkFunc {
+		reqs := requirements
+
+		if point != -1 {
+			var values []uint
+			for v := uint(0); v < parent.Permutations(); v++ {
+				if nWiseReaches(parent, v, i) {
+					values = append(values, v)
+				}
+			}
+
+			reqs = make([]nWiseRequirement, len(requirements), len(requirements)+1)
+			copy(reqs, requirements)
+			reqs = append(reqs, nWiseRequirement{
+				point:  point,
+				values: values,
+			})
+		}
+
+		p := -1
+		if isNWiseChoice(tok) {
+			p = len(s.choices)
+			s.points[path] = p
+
+			s.choices = append(s.choices, nWiseChoice{
+				permutations: tok.Permutations(),
+				requirements: reqs,
+			})
+		}
+
+		return s.findChoices(p, tok, reqs)
+	}
+}
+
+// combine computes all combinations of values of n choice points which can be reached together
+func (s *nWise) combine(n int) {
+	if n > len(s.choices) {
+		n = len(s.choices)
+	}
+
+	s.tuplesOf = make([][][]*nWiseTuple, len(s.choices))
+	for i, c := range s.choices {
+		s.tuplesOf[i] = make([][]*nWiseTuple, c.permutations)
+	}
+
+	if n == 0 {
+		return
+	}
+
+	points := make([]int, n)
+	values := make([]nWiseValue, n)
+
+	var combineValues func(i int)
+	combineValues = func(i int) {
+		if i == n {
+			if !s.reachable(values) {
+				return
+			}
+
+			t := &nWiseTuple{
+				values: make([]nWiseValue, n),
+			}
+			copy(t.values, values)
+
+			s.tuples = append(s.tuples, t)
+			for _, v := range t.values {
+				s.tuplesOf[v.point][v.value] = append(s.tuplesOf[v.point][v.value], t)
+			}
+
+			return
+		}
+
+		for v := uint(0); v < s.choices[points[i]].permutations; v++ {
+			values[i] = nWiseValue{
+				point: points[i],
+				value: v,
+			}
+
+			combineValues(i + 1)
+		}
+	}
+
+	var combinePoints func(i int, from int)
+	combinePoints = func(i int, from int) {
+		if i == n {
+			combineValues(0)
+
+			return
+		}
+
+		for p := from; p < len(s.choices); p++ {
+			points[i] = p
+
+			combinePoints(i+1, p+1)
+		}
+	}
+
+	combinePoints(0, 0)
+}
+
+// constrain returns the allowed values of every choice point which are needed to reach the given values.
+// False is returned if the values cannot be reached together.
+func (s *nWise) constrain(values []nWiseValue) (map[int][]uint, bool) {
+	allowed := make(map[int][]uint)
+
+	restrict := func(point int, values []uint) bool {
+		current, ok := allowed[point]
+		if !ok {
+			allowed[point] = values
+
+			return len(values) != 0
+		}
+
+		var intersection []uint
+		for _, a := range current {
+			for _, b := range values {
+				if a == b {
+					intersection = append(intersection, a)
+
+					break
+				}
+			}
+		}
+
+		allowed[point] = intersection
+
+		return len(intersection) != 0
+	}
+
+	for _, v := range values {
+		if !restrict(v.point, []uint{v.value}) {
+			return nil, false
+		}
+
+		for _, req := range s.choices[v.point].requirements {
+			if !restrict(req.point, req.values) {
+				return nil, false
+			}
+		}
+	}
+
+	return allowed, true
+}
+
+func (s *nWise) reachable(values []nWiseValue) bool {
+	_, ok := s.constrain(values)
+
+	return ok
+}
+
+// generate generates a permutation of the graph which covers the given tuple and as many other uncovered tuples as possible
+func (s *nWise) generate(target *nWiseTuple, r rand.Rand) {
+	log.Debugf("cover %v", target.values)
+
+	assignment := make([]int, len(s.choices))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+
+	allowed, _ := s.constrain(target.values)
+	for p, values := range allowed {
+		assignment[p] = int(values[r.Intn(len(values))])
+	}
+
+	for p, c := range s.choices {
+		if assignment[p] != -1 {
+			continue
+		}
+
+		var best []uint
+		bestScore := -1
+
+		for v := uint(0); v < c.permutations; v++ {
+			score := 0
+
+		TUPLES:
+			for _, t := range s.tuplesOf[p][v] {
+				if t.covered {
+					continue
+				}
+
+				for _, tv := range t.values {
+					if tv.point != p && assignment[tv.point] != int(tv.value) {
+						continue TUPLES
+					}
+				}
+
+				score++
+			}
+
+			if score > bestScore {
+				best = []uint{v}
+				bestScore = score
+			} else if score == bestScore {
+				best = append(best, v)
+			}
+		}
+
+		assignment[p] = int(best[r.Intn(len(best))])
+	}
+
+	reached := make([]map[uint]struct{}, len(s.choices))
+	for i := range reached {
+		reached[i] = make(map[uint]struct{})
+	}
+
+	fuzzPath(s.root, "", func(tok token.Token, path string) (uint, bool) {
+		point, ok := s.points[path]
+		if !ok || !isNWiseChoice(tok) || uint(assignment[point]) >= tok.Permutations() {
+			return 0, false
+		}
+
+		i := uint(assignment[point])
+
+		reached[point][i] = struct{}{}
+
+		return i, true
+	}, r, token.NewVariableScope())
+
+	for _, t := range s.tuples {
+		if t.covered {
+			continue
+		}
+
+		t.covered = true
+
+		for _, v := range t.values {
+			if _, ok := reached[v.point][v.value]; !ok {
+				t.covered = false
+
+				break
+			}
+		}
+	}
+
+	if !target.covered {
+		log.Debugf("could not cover %v", target.values)
+
+		// the graph is too dynamic to cover the target so it is given up to not get stuck
+		target.covered = true
+	}
+}
+
+// isNWiseChoice returns true if the token is a choice point
+func isNWiseChoice(tok token.Token) bool {
+	switch tok.(type) {
+	case *lists.One, *constraints.Optional, *lists.Repeat:
+		return tok.Permutations() > 1
+	}
+
+	return false
+}
+
+// nWiseReaches returns true if the internal child with the given index of the choice point is reached with the given value
+func nWiseReaches(tok token.Token, value uint, child int) bool {
+	switch t := tok.(type) {
+	case *lists.One:
+		return int(value) == child
+	case *constraints.Optional:
+		return value == 1
+	case *lists.Repeat:
+		return t.From()+int64(value) > 0
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+package strategy
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token"
+)
+
+func nWiseStrings(t *testing.T, root token.Token, strat Strategy) [][]string {
+	ch, err := strat(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	var outs [][]string
+
+	for i := range ch {
+		outs = append(outs, strings.Split(root.String(), ","))
+
+		ch <- i
+	}
+
+	return outs
+}
+
+func TestNWiseStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = ("a" | "b" | "c") "," ("a" | "b" | "c") "," ("a" | "b") "," ?("x") "," *("r") "," ("a" | "b" | "c") "," ("a" | "b")
+	`))
+	Nil(t, err)
+
+	domains := [][]string{
+		{"a", "b", "c"},
+		{"a", "b", "c"},
+		{"a", "b"},
+		{"", "x"},
+		{"", "r", "rr"},
+		{"a", "b", "c"},
+		{"a", "b"},
+	}
+
+	covered := func(outs [][]string, points []int, values []string) bool {
+	OUTS:
+		for _, out := range outs {
+			for i, p := range points {
+				if out[p] != values[i] {
+					continue OUTS
+				}
+			}
+
+			return true
+		}
+
+		return false
+	}
+
+	// every value is generated at least once
+	outs := nWiseStrings(t, root, NewNWise(1))
+	Equal(t, 3, len(outs))
+
+	for p, d := range domains {
+		for _, v := range d {
+			True(t, covered(outs, []int{p}, []string{v}), "%d=%q", p, v)
+		}
+	}
+
+	// every pair of values is generated at least once
+	outs = nWiseStrings(t, root, NewNWise(2))
+	True(t, len(outs) >= 9)
+	True(t, len(outs) < 20)
+
+	for p1 := range domains {
+		for p2 := p1 + 1; p2 < len(domains); p2++ {
+			for _, v1 := range domains[p1] {
+				for _, v2 := range domains[p2] {
+					True(t, covered(outs, []int{p1, p2}, []string{v1, v2}), "%d=%q %d=%q", p1, v1, p2, v2)
+				}
+			}
+		}
+	}
+
+	// every triple of values is generated at least once
+	outs = nWiseStrings(t, root, NewNWise(3))
+	True(t, len(outs) >= 27)
+	True(t, len(outs) < 1296)
+
+	for p1 := range domains {
+		for p2 := p1 + 1; p2 < len(domains); p2++ {
+			for p3 := p2 + 1; p3 < len(domains); p3++ {
+				for _, v1 := range domains[p1] {
+					for _, v2 := range domains[p2] {
+						for _, v3 := range domains[p3] {
+							True(t, covered(outs, []int{p1, p2, p3}, []string{v1, v2, v3}), "%d=%q %d=%q %d=%q", p1, v1, p2, v2, p3, v3)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	Panics(t, func() {
+		NewNWise(0)
+	})
+}
+
+func TestNWiseStrategyNested(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Inner = ("a" | "b" ?("c"))
+
+		START = Inner "," ("d" | "e") "," +1,2(Inner)
+	`))
+	Nil(t, err)
+
+	seen := make(map[string]struct{})
+
+	for _, out := range nWiseStrings(t, root, NewNWise(2)) {
+		seen[out[0]+","+out[1]] = struct{}{}
+
+		// every generation is still valid
+		Nil(t, parser.ParseInternal(root.Clone(), strings.NewReader(strings.Join(out, ","))))
+	}
+
+	// the optional token is only reachable with the second alternative
+	Equal(t, map[string]struct{}{
+		"a,d":  struct{}{},
+		"a,e":  struct{}{},
+		"b,d":  struct{}{},
+		"b,e":  struct{}{},
+		"bc,d": struct{}{},
+		"bc,e": struct{}{},
+	}, seen)
+}
+
+func TestNWiseStrategyWithoutChoices(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = "a" "," "b"
+	`))
+	Nil(t, err)
+
+	Equal(t, [][]string{{"a", "b"}}, nWiseStrings(t, root, NewNWise(2)))
+}
+
+func TestNWiseStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewNWise(2))
+}
@@ -169,7 +169,7 @@ func (s *permuteOptionals) findOptionals(r rand.Rand, root token.Token, fromChil
 			c := t.Get()
 
 			if c != nil {
-				err := c.Permutation(uint(r.Intn(int(c.Permutations()))))
+				err := c.Permutation(randomPermutation(c, r))
 				if err != nil {
 					log.Panic(err)
 				}
@@ -180,7 +180,7 @@ func (s *permuteOptionals) findOptionals(r rand.Rand, root token.Token, fromChil
 			for i := t.Len() - 1; i >= 0; i-- {
 				c, _ := t.Get(i)
 
-				err := c.Permutation(uint(r.Intn(int(c.Permutations()))))
+				err := c.Permutation(randomPermutation(c, r))
 				if err != nil {
 					log.Panic(err)
 				}
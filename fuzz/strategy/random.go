@@ -1,75 +1,155 @@
 package strategy
 
 import (
+	mathRand "math/rand"
+
 	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/rand"
 	"github.com/zimmski/tavor/token"
 	"github.com/zimmski/tavor/token/sequences"
 )
 
 func init() {
-	Register("random", NewRandom)
+	RegisterOptions("random", []option.Option{
+		{
+			Name:        "count",
+			Type:        option.Int,
+			Default:     "1",
+			Description: "How many generations should be generated",
+		},
+		{
+			Name:        "depth",
+			Type:        option.Int,
+			Default:     "0",
+			Description: "The depth of the token graph after which tokens get their first permutation, 0 means that the depth is not limited",
+		},
+		{
+			Name:        "seed",
+			Type:        option.Int,
+			Default:     "0",
+			Description: "The seed of an own random generator for this run, 0 means that the global random generator is used",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		if values.Int("count") < 1 {
+			return nil, &option.Error{
+				Message: "option count must be at least 1",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+		if values.Int("depth") < 0 {
+			return nil, &option.Error{
+				Message: "option depth must not be negative",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewRandomWithOptions(values.Int("count"), values.Int("depth"), int64(values.Int("seed"))), nil
+	})
 }
 
 type random struct {
 	root token.Token
+
+	depth int
 }
 
 // NewRandom implements a fuzzing strategy that generates a random permutation of a token graph.
 // The strategy does exactly one iteration which permutates at random all reachable tokens in the graph. The determinism is dependent on the random generator and is therefore for example deterministic if a seed for the random generator produces always the same outputs.
 func NewRandom(root token.Token, r rand.Rand) (chan struct{}, error) {
-	if r == nil {
-		return nil, &Error{
-			Message: "random generator is nil",
-			Type:    ErrNilRandomGenerator,
+	return NewRandomIterations(1)(root, r)
+}
+
+// NewRandomIterations returns a fuzzing strategy that generates the given number of random permutations of a token graph.
+// Every iteration permutates at random all reachable tokens in the graph like the random strategy.
+func NewRandomIterations(iterations int) Strategy {
+	return NewRandomWithOptions(iterations, 0, 0)
+}
+
+// NewRandomWithOptions returns a fuzzing strategy that generates the given number of random permutations of a token graph like NewRandomIterations.
+// Tokens which are deeper in the graph than the given depth are not permutated at random but get their first permutation, e.g. the first alternative of a list or the minimum repetition, which keeps the generations of deep graphs small. A depth of 0 does not limit the depth. If the given seed is not 0 the run uses its own random generator with this seed instead of the given one, which makes the run reproducible independently of the given random generator.
+func NewRandomWithOptions(iterations int, depth int, seed int64) Strategy {
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
 		}
-	}
 
-	if token.LoopExists(root) {
-		return nil, &Error{
-			Message: "found endless loop in graph. Cannot proceed.",
-			Type:    ErrEndlessLoopDetected,
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
 		}
-	}
 
-	s := &random{
-		root: root,
-	}
+		s := &random{
+			root: root,
 
-	continueFuzzing := make(chan struct{})
+			depth: depth,
+		}
 
-	go func() {
-		log.Debug("start random fuzzing routine")
+		if seed != 0 {
+			log.Infof("use random generator with seed %d for the random fuzzing strategy", seed)
+
+			r = mathRand.New(mathRand.NewSource(seed))
+		}
 
-		s.fuzz(s.root, r, token.NewVariableScope())
+		continueFuzzing := make(chan struct{})
 
-		s.fuzzYADDA(s.root, r)
+		go func() {
+			log.Debug("start random fuzzing routine")
 
-		log.Debug("done with fuzzing step")
+			for i := 0; i < iterations; i++ {
+				s.fuzz(s.root, r, token.NewVariableScope())
 
-		// done with the last fuzzing step
-		continueFuzzing <- struct{}{}
+				fuzzYADDA(s.root, r)
 
-		log.Debug("finished fuzzing. Wait till the outside is ready to close.")
+				log.Debug("done with fuzzing step")
 
-		if _, ok := <-continueFuzzing; ok {
-			log.Debug("close fuzzing channel")
+				// done with this fuzzing step
+				continueFuzzing <- struct{}{}
+
+				// wait until we are allowed to continue
+				if _, ok := <-continueFuzzing; !ok {
+					log.Debug("fuzzing channel closed from outside")
+
+					return
+				}
+			}
+
+			log.Debug("finished fuzzing.")
 
 			close(continueFuzzing)
-		}
-	}()
+		}()
 
-	return continueFuzzing, nil
+		return continueFuzzing, nil
+	}
 }
 
 func (s *random) fuzz(tok token.Token, r rand.Rand, variableScope *token.VariableScope) {
+	s.fuzzDepth(tok, r, variableScope, 0)
+}
+
+// fuzzDepth permutates the token, which is at the given depth of the graph, and its children
+func (s *random) fuzzDepth(tok token.Token, r rand.Rand, variableScope *token.VariableScope, depth int) {
 	log.Debugf("Fuzz (%p)%#v with maxPermutations %d", tok, tok, tok.Permutations())
 
 	if t, ok := tok.(token.Scoping); ok && t.Scoping() {
 		variableScope = variableScope.Push()
 	}
 
-	err := tok.Permutation(uint(r.Int63n(int64(tok.Permutations()))))
+	var i uint
+	if s.depth > 0 && depth >= s.depth {
+		i = 0
+	} else if t, ok := tok.(token.Weighted); ok && t.Weights() != nil {
+		i = weightedPermutation(t.Weights(), r)
+	} else {
+		i = randomPermutation(tok, r)
+	}
+
+	err := tok.Permutation(i)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -78,14 +158,14 @@ func (s *random) fuzz(tok token.Token, r rand.Rand, variableScope *token.Variabl
 		switch t := tok.(type) {
 		case token.ForwardToken:
 			if v := t.Get(); v != nil {
-				s.fuzz(v, r, variableScope)
+				s.fuzzDepth(v, r, variableScope, depth+1)
 			}
 		case token.ListToken:
 			l := t.Len()
 
 			for i := 0; i < l; i++ {
 				c, _ := t.Get(i)
-				s.fuzz(c, r, variableScope)
+				s.fuzzDepth(c, r, variableScope, depth+1)
 			}
 		}
 	}
@@ -95,7 +175,7 @@ func (s *random) fuzz(tok token.Token, r rand.Rand, variableScope *token.Variabl
 	}
 }
 
-func (s *random) fuzzYADDA(root token.Token, r rand.Rand) {
+func fuzzYADDA(root token.Token, r rand.Rand) {
 	// TODO FIXME AND FIXME FIXME FIXME this should be done automatically somehow
 	// since this doesn't work in other heuristics...
 	// especially the fuzz again part is tricky. the whole reason is because of dynamic repeats that clone during a reset. so the "reset" or regenerating of new child tokens has to be done better
@@ -109,7 +189,7 @@ func (s *random) fuzzYADDA(root token.Token, r rand.Rand) {
 		case *sequences.SequenceExistingItem:
 			log.Debugf("Fuzz again %p(%#v)", tok, tok)
 
-			err := tok.Permutation(uint(r.Int63n(int64(tok.Permutations()))))
+			err := tok.Permutation(randomPermutation(tok, r))
 			if err != nil {
 				log.Panic(err)
 			}
@@ -121,3 +201,23 @@ func (s *random) fuzzYADDA(root token.Token, r rand.Rand) {
 		panic(err)
 	}
 }
+
+// weightedPermutation returns a random permutation index with respect to the given weights
+func weightedPermutation(weights []int, r rand.Rand) uint {
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+
+	n := int(r.Int63n(int64(sum)))
+
+	for i, w := range weights {
+		if n < w {
+			return uint(i)
+		}
+
+		n -= w
+	}
+
+	panic("unreachable")
+}
@@ -6,7 +6,9 @@ import (
 
 	. "github.com/zimmski/tavor/test/assert"
 
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/rand"
 	"github.com/zimmski/tavor/test"
 	"github.com/zimmski/tavor/token/constraints"
 	"github.com/zimmski/tavor/token/lists"
@@ -138,6 +140,29 @@ func TestRandomStrategyCases(t *testing.T) {
 	}
 }
 
+func TestRandomStrategyWeights(t *testing.T) {
+	o := lists.NewOne(
+		primitives.NewConstantString("a"),
+		primitives.NewConstantString("b"),
+		primitives.NewConstantString("c"),
+	)
+	Nil(t, o.SetWeights([]int{3, 1, 2}))
+
+	for i, expect := range []string{"a", "a", "a", "b", "c", "c", "a"} {
+		r := test.NewRandTest(int64(i))
+
+		ch, err := NewRandom(o, r)
+		Nil(t, err)
+
+		_, ok := <-ch
+		True(t, ok)
+
+		Equal(t, expect, o.String())
+
+		close(ch)
+	}
+}
+
 func validateTavorRandom(t *testing.T, seed int, format string, expect []string) {
 	root, err := parser.ParseTavor(strings.NewReader(format))
 	Nil(t, err)
@@ -161,6 +186,65 @@ func validateTavorRandom(t *testing.T, seed int, format string, expect []string)
 	Equal(t, got, expect)
 }
 
+func TestRandomStrategyOptions(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = +1,2(Item)
+
+		Item = "a" | "b" | "c" | "d"
+	`))
+	Nil(t, err)
+
+	generate := func(strat Strategy, r rand.Rand) []string {
+		ch, err := strat(root, r)
+		Nil(t, err)
+
+		var generations []string
+		for i := range ch {
+			generations = append(generations, root.String())
+
+			ch <- i
+		}
+
+		return generations
+	}
+
+	// the same seed leads to the same generations independent of the given random generator
+	strat, err := NewWithOptions("random", map[string]string{
+		"count": "10",
+		"seed":  "7",
+	})
+	Nil(t, err)
+
+	r := test.NewRandTest(1)
+	Equal(t, generate(strat, r), generate(strat, test.NewRandTest(100)))
+
+	// the given random generator is not used
+	Equal(t, test.NewRandTest(1).Int63(), r.Int63())
+
+	// tokens deeper than the depth get their first permutation
+	strat, err = NewWithOptions("random", map[string]string{
+		"count": "10",
+		"depth": "1",
+	})
+	Nil(t, err)
+
+	for _, g := range generate(strat, test.NewRandTest(1)) {
+		Equal(t, "a", g)
+	}
+
+	strat, err = NewWithOptions("random", map[string]string{
+		"count": "0",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("random", map[string]string{
+		"depth": "-1",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+}
+
 func TestRandomStrategyLoopDetection(t *testing.T) {
 	testStrategyLoopDetection(t, NewRandom)
 }
@@ -2,8 +2,12 @@ package strategy
 
 import (
 	"fmt"
+	"math/big"
 	"sort"
+	"strconv"
 
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/rand"
 	"github.com/zimmski/tavor/token"
 )
@@ -16,6 +20,10 @@ const (
 	ErrEndlessLoopDetected ErrorType = iota
 	// ErrNilRandomGenerator the random generator is nil
 	ErrNilRandomGenerator
+	// ErrNoPermutationWithinSize there is no permutation within the size bound
+	ErrNoPermutationWithinSize
+	// ErrNoSeeds there are no seeds to work with
+	ErrNoSeeds
 )
 
 // Error holds a fuzzing strategy error
@@ -32,7 +40,40 @@ func (err *Error) Error() string {
 // The function starts the first iteration of the fuzzing strategy returning a channel which controls the iteration flow. The channel returns a value if the iteration is complete and waits with calculating the next iteration until a value is put in. The channel is automatically closed when there are no more iterations. The error return argument is not nil if an error occurs during the setup of the fuzzing strategy.
 type Strategy func(root token.Token, r rand.Rand) (chan struct{}, error)
 
+// Feedback holds the feedback of the execution of one fuzzing generation
+type Feedback struct {
+	// Coverage holds the identifiers of the behaviour, e.g. covered code blocks, which was triggered by the generation
+	Coverage []string
+	// Score rates the generation, a higher score is better
+	Score float64
+}
+
+// FeedbackStrategy defines a fuzzing strategy which is guided by the feedback of its generations.
+// In addition to the control channel of a Strategy a channel for the feedback of every iteration is returned. A feedback has to be given after every iteration before a value is put into the control channel. The channels must be closed by the caller if no more iterations are needed.
+type FeedbackStrategy func(root token.Token, r rand.Rand) (chan struct{}, chan<- Feedback, error)
+
+// NewWithOptionsFunc returns a fuzzing strategy instance for the given option values.
+// The error return argument is not nil, if the values are not valid for the strategy.
+type NewWithOptionsFunc func(values option.Values) (Strategy, error)
+
+// NewFeedbackWithOptionsFunc returns a feedback fuzzing strategy instance for the given option values.
+// The error return argument is not nil, if the values are not valid for the strategy.
+type NewFeedbackWithOptionsFunc func(values option.Values) (FeedbackStrategy, error)
+
+type strategyOptions struct {
+	options []option.Option
+	new     NewWithOptionsFunc
+}
+
+type feedbackStrategyOptions struct {
+	options []option.Option
+	new     NewFeedbackWithOptionsFunc
+}
+
 var strategyLookup = make(map[string]Strategy)
+var strategyOptionsLookup = make(map[string]strategyOptions)
+var feedbackStrategyLookup = make(map[string]FeedbackStrategy)
+var feedbackStrategyOptionsLookup = make(map[string]feedbackStrategyOptions)
 
 // New returns a new fuzzing strategy instance given the registered name of the strategy.
 // The error return argument is not nil, if the name does not exist in the registered fuzzing strategy list.
@@ -70,3 +111,309 @@ func Register(name string, strat Strategy) {
 
 	strategyLookup[name] = strat
 }
+
+// Options returns the declared options of the fuzzing strategy with the given registered name.
+func Options(name string) []option.Option {
+	return strategyOptionsLookup[name].options
+}
+
+// NewWithOptions returns a new fuzzing strategy instance given the registered name of the strategy and the arguments for its options.
+// Options which are not given as argument have their default value. The error return argument is not nil, if the name does not exist in the registered fuzzing strategy list or if the arguments are not valid for the options of the strategy.
+func NewWithOptions(name string, arguments map[string]string) (Strategy, error) {
+	strat, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := option.Parse(Options(name), arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := strategyOptionsLookup[name]; ok {
+		return o.new(values)
+	}
+
+	return strat, nil
+}
+
+// RegisterOptions registers a fuzzing strategy instance function with the given name and declared options.
+// The strategy is also registered as a fuzzing strategy instance with the default values of its options.
+func RegisterOptions(name string, options []option.Option, new NewWithOptionsFunc) {
+	if new == nil {
+		panic("register fuzzing strategy is nil")
+	}
+
+	strat, err := new(strategyOptionsDefaults(options))
+	if err != nil {
+		panic(err)
+	}
+
+	Register(name, strat)
+
+	strategyOptionsLookup[name] = strategyOptions{
+		options: options,
+		new:     new,
+	}
+}
+
+// NewFeedback returns a new feedback fuzzing strategy instance given the registered name of the strategy.
+// The error return argument is not nil, if the name does not exist in the registered feedback fuzzing strategy list.
+func NewFeedback(name string) (FeedbackStrategy, error) {
+	strat, ok := feedbackStrategyLookup[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown feedback fuzzing strategy %q", name)
+	}
+
+	return strat, nil
+}
+
+// RegisterFeedback registers a feedback fuzzing strategy instance function with the given name.
+// The strategy is also registered as a fuzzing strategy which gets an empty feedback for every iteration.
+func RegisterFeedback(name string, strat FeedbackStrategy) {
+	if strat == nil {
+		panic("register feedback fuzzing strategy is nil")
+	}
+
+	Register(name, WithoutFeedback(strat))
+
+	feedbackStrategyLookup[name] = strat
+}
+
+// NewFeedbackWithOptions returns a new feedback fuzzing strategy instance given the registered name of the strategy and the arguments for its options.
+// Options which are not given as argument have their default value. The error return argument is not nil, if the name does not exist in the registered feedback fuzzing strategy list or if the arguments are not valid for the options of the strategy.
+func NewFeedbackWithOptions(name string, arguments map[string]string) (FeedbackStrategy, error) {
+	strat, err := NewFeedback(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := option.Parse(Options(name), arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := feedbackStrategyOptionsLookup[name]; ok {
+		return o.new(values)
+	}
+
+	return strat, nil
+}
+
+// RegisterFeedbackOptions registers a feedback fuzzing strategy instance function with the given name and declared options.
+// The strategy is also registered as a fuzzing strategy with options which gets an empty feedback for every iteration.
+func RegisterFeedbackOptions(name string, options []option.Option, new NewFeedbackWithOptionsFunc) {
+	if new == nil {
+		panic("register feedback fuzzing strategy is nil")
+	}
+
+	RegisterOptions(name, options, func(values option.Values) (Strategy, error) {
+		strat, err := new(values)
+		if err != nil {
+			return nil, err
+		}
+
+		return WithoutFeedback(strat), nil
+	})
+
+	strat, err := new(strategyOptionsDefaults(options))
+	if err != nil {
+		panic(err)
+	}
+
+	feedbackStrategyLookup[name] = strat
+	feedbackStrategyOptionsLookup[name] = feedbackStrategyOptions{
+		options: options,
+		new:     new,
+	}
+}
+
+// strategyOptionsDefaults returns the default values of the given options
+func strategyOptionsDefaults(options []option.Option) option.Values {
+	values, err := option.Parse(options, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return values
+}
+
+// WithoutFeedback returns a fuzzing strategy for the given feedback fuzzing strategy which gives an empty feedback for every iteration
+func WithoutFeedback(strat FeedbackStrategy) Strategy {
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		continueFeedback, feedback, err := strat(root, r)
+		if err != nil {
+			return nil, err
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			for range continueFeedback {
+				continueFuzzing <- struct{}{}
+
+				if _, ok := <-continueFuzzing; !ok {
+					close(feedback)
+					close(continueFeedback)
+
+					return
+				}
+
+				feedback <- Feedback{}
+				continueFeedback <- struct{}{}
+			}
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// randomPermutation returns a random permutation index of the given token
+// The index is uniformly chosen out of all permutations of the token. Since Permutation only accepts indices below Permutations, the choice is restricted to them if the uint count of the permutations is smaller than the arbitrary-precision count.
+func randomPermutation(tok token.Token, r rand.Rand) uint {
+	permutations := tok.PermutationsBig()
+
+	if accepted := new(big.Int).SetUint64(uint64(tok.Permutations())); accepted.Cmp(permutations) < 0 {
+		permutations = accepted
+	}
+
+	if permutations.Sign() <= 0 {
+		return 0
+	}
+
+	return uint(randomBigInt(permutations, r).Uint64())
+}
+
+// randomBigInt returns a random integer in [0,n)
+func randomBigInt(n *big.Int, r rand.Rand) *big.Int {
+	if n.IsInt64() {
+		return big.NewInt(r.Int63n(n.Int64()))
+	}
+
+	// concatenate more random bits than needed to make the bias of the modulo negligible
+	v := new(big.Int)
+	for bits := 0; bits < n.BitLen()+64; bits += 63 {
+		v.Lsh(v, 63)
+		v.Or(v, big.NewInt(r.Int63()))
+	}
+
+	return v.Mod(v, n)
+}
+
+// pathWalkFunc is called by walkPaths for every token of the internal structure of a graph together with its path and its index in the internal children of its parent.
+// The returned function is called for the internal children of the token. If it is nil, the children are not walked.
+type pathWalkFunc func(tok token.Token, path string, i int) pathWalkFunc
+
+// walkPaths walks the internal structure of the graph with the given children function. Every token is identified by the path of internal child indices from the root.
+// Recursive definitions and tokens which must not be followed are not walked into.
+func walkPaths(root token.Token, children func(tok token.Token) []token.Token, walk pathWalkFunc) {
+	walkPath(root, "", 0, children, walk, make(map[token.Token]struct{}))
+}
+
+func walkPath(tok token.Token, path string, i int, children func(tok token.Token) []token.Token, walk pathWalkFunc, visited map[token.Token]struct{}) {
+	// do not follow recursive definitions
+	if _, ok := visited[tok]; ok {
+		return
+	}
+	visited[tok] = struct{}{}
+	defer delete(visited, tok)
+
+	next := walk(tok, path, i)
+	if next == nil {
+		return
+	}
+
+	if t, ok := tok.(token.Follow); ok && !t.Follow() {
+		return
+	}
+
+	for j, c := range children(tok) {
+		walkPath(c, path+"."+strconv.Itoa(j), j, children, next, visited)
+	}
+}
+
+// pathChooseFunc chooses the permutation of a token which is identified by the path of internal child indices from the root.
+// The return argument is false if the permutation should be chosen at random.
+type pathChooseFunc func(tok token.Token, path string) (uint, bool)
+
+// fuzzPath permutates the token and its children like the random strategy but lets the given function choose the permutations.
+// The tokens are identified like with walkPaths, which means that the clones of a repeat share the path of the repeated internal token.
+func fuzzPath(tok token.Token, path string, choose pathChooseFunc, r rand.Rand, variableScope *token.VariableScope) {
+	log.Debugf("Fuzz (%p)%#v with path %s", tok, tok, path)
+
+	if t, ok := tok.(token.Scoping); ok && t.Scoping() {
+		variableScope = variableScope.Push()
+	}
+
+	i, ok := choose(tok, path)
+	if !ok {
+		if t, ok := tok.(token.Weighted); ok && t.Weights() != nil {
+			i = weightedPermutation(t.Weights(), r)
+		} else {
+			i = randomPermutation(tok, r)
+		}
+	}
+
+	err := tok.Permutation(i)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if t, ok := tok.(token.Follow); !ok || t.Follow() {
+		switch t := tok.(type) {
+		case token.ForwardToken:
+			if v := t.Get(); v != nil {
+				fuzzPath(v, path+".0", choose, r, variableScope)
+			}
+		case token.ListToken:
+			l := t.Len()
+
+			for i := 0; i < l; i++ {
+				c, _ := t.Get(i)
+
+				fuzzPath(c, path+"."+strconv.Itoa(internalIndex(t, c, i)), choose, r, variableScope)
+			}
+		}
+	}
+
+	if t, ok := tok.(token.Scoping); ok && t.Scoping() {
+		variableScope = variableScope.Pop()
+	}
+}
+
+// internalIndex returns the index of the internal token the given child of the list originates from
+func internalIndex(list token.ListToken, child token.Token, i int) int {
+	for j := 0; j < list.InternalLen(); j++ {
+		if c, _ := list.InternalGet(j); c == child {
+			return j
+		}
+	}
+
+	// the child is a clone of the only internal token, e.g. of a repeat
+	if list.InternalLen() == 1 {
+		return 0
+	}
+
+	return i
+}
+
+// internalChildren returns the internal children of the token
+func internalChildren(tok token.Token) []token.Token {
+	var children []token.Token
+
+	switch t := tok.(type) {
+	case token.ForwardToken:
+		if c := t.InternalGet(); c != nil {
+			children = append(children, c)
+		}
+	case token.ListToken:
+		for i := 0; i < t.InternalLen(); i++ {
+			c, _ := t.InternalGet(i)
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
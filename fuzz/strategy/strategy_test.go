@@ -1,10 +1,12 @@
 package strategy
 
 import (
+	"math"
 	"testing"
 
 	. "github.com/zimmski/tavor/test/assert"
 
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/rand"
 	"github.com/zimmski/tavor/test"
 	"github.com/zimmski/tavor/token"
@@ -80,6 +82,79 @@ func TestStrategy(t *testing.T) {
 	True(t, caught)
 }
 
+func TestStrategyOptions(t *testing.T) {
+	strat, err := NewWithOptions("random", map[string]string{
+		"count": "3",
+	})
+	Nil(t, err)
+
+	root := primitives.NewRangeInt(1, 9)
+
+	ch, err := strat(root, test.NewRandTest(1))
+	Nil(t, err)
+
+	generations := 0
+	for i := range ch {
+		generations++
+
+		ch <- i
+	}
+	Equal(t, 3, generations)
+
+	Equal(t, "count", Options("random")[0].Name)
+	Nil(t, Options("AllPermutations"))
+
+	strat, err = NewWithOptions("random", map[string]string{
+		"count": "many",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("AllPermutations", map[string]string{
+		"count": "3",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrUnknownOption, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("NWise", map[string]string{
+		"n": "0",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("Swarm", map[string]string{
+		"batches": "0",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("Swarm", map[string]string{
+		"batch-size": "-1",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("mockachino", nil)
+	Nil(t, strat)
+	NotNil(t, err)
+
+	feedbackStrat, err := NewFeedbackWithOptions("Genetic", map[string]string{
+		"population": "5",
+	})
+	NotNil(t, feedbackStrat)
+	Nil(t, err)
+
+	feedbackStrat, err = NewFeedbackWithOptions("Genetic", map[string]string{
+		"population": "1",
+	})
+	Nil(t, feedbackStrat)
+	Equal(t, option.ErrInvalidValue, err.(*option.Error).Type)
+
+	feedbackStrat, err = NewFeedbackWithOptions("random", nil)
+	Nil(t, feedbackStrat)
+	NotNil(t, err)
+}
+
 func testStrategyLoopDetection(t *testing.T, newStrategy Strategy) {
 	var tok *token.Token
 	r := test.NewRandTest(1)
@@ -147,3 +222,21 @@ func testStrategyLoopDetection(t *testing.T, newStrategy Strategy) {
 		Equal(t, ErrEndlessLoopDetected, err.(*Error).Type)
 	}
 }
+
+func TestRandomPermutation(t *testing.T) {
+	r := test.NewRandTest(1)
+
+	tok := primitives.NewRangeInt(1, 3)
+	for i := 0; i < 10; i++ {
+		p := randomPermutation(tok, r)
+		True(t, p < 3)
+	}
+
+	// the index is always accepted by the token even if there are more permutations than a uint can hold
+	tok = primitives.NewRangeInt(-10, math.MaxInt64)
+	for i := 0; i < 10; i++ {
+		p := randomPermutation(tok, r)
+		True(t, p < tok.Permutations())
+		Nil(t, tok.Permutation(p))
+	}
+}
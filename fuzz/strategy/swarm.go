@@ -0,0 +1,237 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/constraints"
+	"github.com/zimmski/tavor/token/lists"
+)
+
+func init() {
+	RegisterOptions("Swarm", []option.Option{
+		{
+			Name:        "batches",
+			Type:        option.Int,
+			Default:     "10",
+			Description: "How many batches should be generated",
+		},
+		{
+			Name:        "batch-size",
+			Type:        option.Int,
+			Default:     "10",
+			Description: "How many generations should be generated for every batch",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		for _, name := range []string{"batches", "batch-size"} {
+			if values.Int(name) < 1 {
+				return nil, &option.Error{
+					Message: fmt.Sprintf("option %s must be at least 1", name),
+					Type:    option.ErrInvalidValue,
+				}
+			}
+		}
+
+		return NewSwarm(values.Int("batches"), values.Int("batch-size")), nil
+	})
+}
+
+type swarm struct {
+	root token.Token
+
+	// choices holds the paths of the choice tokens of the internal structure of the graph
+	choices map[string]token.Token
+	paths   []string
+
+	// allowed holds the allowed permutations of every restricted choice token of the current batch
+	allowed map[string][]uint
+}
+
+// NewSwarm returns a fuzzing strategy which applies swarm testing to the random fuzzing strategy.
+// Before every batch of generations a random subset of the alternatives of lists.One tokens and of the states of constraints.Optional tokens is forbidden. Every alternative is forbidden with a chance of one half, but at least one alternative is always allowed. Every optional token is forbidden to be either present or absent with a chance of one quarter each. The generations of the batch are then permutated at random using only the allowed alternatives and states, which leads to more diverse inputs than the random fuzzing strategy alone. The configuration of every batch is logged so a failing batch can be reproduced. The strategy ends after the given number of batches each consisting of the given number of generations.
+func NewSwarm(batches int, batchSize int) Strategy {
+	if batches < 1 || batchSize < 1 {
+		panic("batches and batch size must be at least 1")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &swarm{
+			root: root,
+
+			choices: make(map[string]token.Token),
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debug("start swarm fuzzing routine")
+
+			walkPaths(s.root, internalChildren, s.findChoices)
+
+			for b := 0; b < batches; b++ {
+				s.configure(r)
+
+				log.Infof("swarm batch %d forbids %s", b+1, s.describe())
+
+				for i := 0; i < batchSize; i++ {
+					fuzzPath(s.root, "", func(tok token.Token, path string) (uint, bool) {
+						return s.choose(tok, path, r)
+					}, r, token.NewVariableScope())
+
+					fuzzYADDA(s.root, r)
+
+					log.Debug("done with fuzzing step")
+
+					// done with this fuzzing step
+					continueFuzzing <- struct{}{}
+
+					// wait until we are allowed to continue
+					if _, ok := <-continueFuzzing; !ok {
+						log.Debug("fuzzing channel closed from outside")
+
+						return
+					}
+				}
+			}
+
+			log.Debug("finished fuzzing.")
+
+			close(continueFuzzing)
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// findChoices searches the internal structure of the graph for choice tokens.
+// Every choice token is identified by its path.
+func (s *swarm) findChoices(tok token.Token, path string, i int) pathWalkFunc {
+	if isSwarmChoice(tok) {
+		s.choices[path] = tok
+		s.paths = append(s.paths, path)
+	}
+
+	return s.findChoices
+}
+
+// configure chooses the forbidden alternatives and states of the next batch
+func (s *swarm) configure(r rand.Rand) {
+	s.allowed = make(map[string][]uint)
+
+	for _, path := range s.paths {
+		tok := s.choices[path]
+
+		switch tok.(type) {
+		case *lists.One:
+			var allowed []uint
+			for v := uint(0); v < tok.Permutations(); v++ {
+				if r.Intn(2) == 0 {
+					allowed = append(allowed, v)
+				}
+			}
+
+			if len(allowed) == 0 {
+				allowed = append(allowed, uint(r.Intn(int(tok.Permutations()))))
+			}
+
+			if uint(len(allowed)) != tok.Permutations() {
+				s.allowed[path] = allowed
+			}
+		case *constraints.Optional:
+			switch r.Intn(4) {
+			case 0:
+				s.allowed[path] = []uint{0}
+			case 1:
+				s.allowed[path] = []uint{1}
+			}
+		}
+	}
+}
+
+// describe returns a description of the forbidden alternatives and states of the current batch
+func (s *swarm) describe() string {
+	var forbidden []string
+
+	for _, path := range s.paths {
+		allowed, ok := s.allowed[path]
+		if !ok {
+			continue
+		}
+
+		tok := s.choices[path]
+
+		switch tok.(type) {
+		case *lists.One:
+			var alternatives []string
+
+		ALTERNATIVES:
+			for v := uint(0); v < tok.Permutations(); v++ {
+				for _, a := range allowed {
+					if a == v {
+						continue ALTERNATIVES
+					}
+				}
+
+				alternatives = append(alternatives, strconv.Itoa(int(v)+1))
+			}
+
+			forbidden = append(forbidden, fmt.Sprintf("alternatives %s of %s at %q", strings.Join(alternatives, ","), describe(tok, 2), path))
+		case *constraints.Optional:
+			forbidden = append(forbidden, fmt.Sprintf("%s state of %s at %q", presentOrAbsent(allowed[0] == 0), describe(tok, 2), path))
+		}
+	}
+
+	if len(forbidden) == 0 {
+		return "nothing"
+	}
+
+	return strings.Join(forbidden, ", ")
+}
+
+// choose chooses one of the allowed permutations of a restricted choice token
+func (s *swarm) choose(tok token.Token, path string, r rand.Rand) (uint, bool) {
+	allowed, ok := s.allowed[path]
+	if !ok || !isSwarmChoice(tok) {
+		return 0, false
+	}
+
+	if t, ok := tok.(token.Weighted); ok && t.Weights() != nil {
+		weights := make([]int, len(allowed))
+		for j, a := range allowed {
+			weights[j] = t.Weights()[a]
+		}
+
+		return allowed[weightedPermutation(weights, r)], true
+	}
+
+	return allowed[r.Intn(len(allowed))], true
+}
+
+// isSwarmChoice returns true if the token is a choice token which can be restricted
+func isSwarmChoice(tok token.Token) bool {
+	switch tok.(type) {
+	case *lists.One, *constraints.Optional:
+		return tok.Permutations() > 1
+	}
+
+	return false
+}
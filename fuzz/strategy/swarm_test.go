@@ -0,0 +1,76 @@
+package strategy
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+)
+
+func TestSwarmStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		Letter = "a" | "b" | "c" | "d"
+
+		START = +5,5(Letter ?("!"))
+	`))
+	Nil(t, err)
+
+	ch, err := NewSwarm(20, 10)(root, rand.New(rand.NewSource(1)))
+	Nil(t, err)
+
+	var batches []map[string]struct{}
+	all := make(map[string]struct{})
+
+	iterations := 0
+
+	for i := range ch {
+		out := root.String()
+
+		// every generation is still valid
+		Nil(t, parser.ParseInternal(root.Clone(), strings.NewReader(out)))
+
+		if iterations%10 == 0 {
+			batches = append(batches, make(map[string]struct{}))
+		}
+
+		for _, c := range out {
+			if c == '!' {
+				continue
+			}
+
+			batches[len(batches)-1][string(c)] = struct{}{}
+			all[string(c)] = struct{}{}
+		}
+
+		iterations++
+
+		ch <- i
+	}
+
+	Equal(t, 200, iterations)
+	Equal(t, 20, len(batches))
+
+	// every alternative is used but most batches are restricted
+	Equal(t, 4, len(all))
+
+	restricted := 0
+	for _, b := range batches {
+		if len(b) < 4 {
+			restricted++
+		}
+	}
+	True(t, restricted > 10)
+}
+
+func TestSwarmStrategyNilRandomGenerator(t *testing.T) {
+	ch, err := NewSwarm(1, 1)(nil, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+}
+
+func TestSwarmStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewSwarm(1, 1))
+}
@@ -0,0 +1,562 @@
+package strategy
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/rand"
+	"github.com/zimmski/tavor/token"
+)
+
+func init() {
+	RegisterOptions("UniformRandom", []option.Option{
+		{
+			Name:        "size",
+			Type:        option.Int,
+			Default:     "0",
+			Description: "The maximum size of a generation in terminal tokens, which are the tokens without children like strings, 0 means that the size is not bound",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		size := values.Int("size")
+		if size < 0 {
+			return nil, &option.Error{
+				Message: "option size must not be negative",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		if size == 0 {
+			return NewUniformRandom, nil
+		}
+
+		return NewUniformRandomWithSize(size), nil
+	})
+	RegisterOptions("BoltzmannRandom", []option.Option{
+		{
+			Name:        "x",
+			Type:        option.Float,
+			Default:     "0.5",
+			Description: "The Boltzmann parameter, values below 1 favour small and values above 1 big generations",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		x := values.Float("x")
+		if x <= 0 {
+			return nil, &option.Error{
+				Message: "option x must be positive",
+				Type:    option.ErrInvalidValue,
+			}
+		}
+
+		return NewBoltzmannRandom(x), nil
+	})
+}
+
+type uniformRandom struct {
+	root token.Token
+
+	// weight returns the weight of all permutations of the given token including its children
+	weight func(tok token.Token) *big.Rat
+}
+
+// NewUniformRandom implements a fuzzing strategy that generates a random permutation of a token graph which is uniformly distributed over all permutations of the graph.
+// In contrast to the random strategy, which permutates every token independently, the permutation of a token is chosen with respect to the number of permutations of its children. Every permutation of the graph, which is bound by tavor.MaxRepeat, is therefore generated with the same probability. The strategy does exactly one iteration.
+func NewUniformRandom(root token.Token, r rand.Rand) (chan struct{}, error) {
+	return newUniformRandom(root, r, func(tok token.Token) *big.Rat {
+		return new(big.Rat).SetInt(tok.PermutationsAllBig())
+	})
+}
+
+// NewUniformRandomWithSize returns a fuzzing strategy that generates a random permutation of a token graph which is uniformly distributed over all permutations of the graph with at most the given size.
+// The size of a permutation is the number of its terminal tokens, which are the tokens without children like constant strings and character classes, and therefore roughly the number of values the generation consists of. First the size of the permutation is chosen with respect to the number of permutations of every size, then the permutations and sizes of the children of every token are chosen with respect to the number of permutations of their children with the remaining size. Every permutation of the graph with at most the given size is therefore generated with the same probability. The strategy does exactly one iteration.
+func NewUniformRandomWithSize(size int) Strategy {
+	if size < 1 {
+		panic("size must be at least 1")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		if r == nil {
+			return nil, &Error{
+				Message: "random generator is nil",
+				Type:    ErrNilRandomGenerator,
+			}
+		}
+
+		if token.LoopExists(root) {
+			return nil, &Error{
+				Message: "found endless loop in graph. Cannot proceed.",
+				Type:    ErrEndlessLoopDetected,
+			}
+		}
+
+		s := &uniformRandomSize{
+			size:       size,
+			structures: newStructures(),
+			memo:       make(map[int][]*big.Int),
+		}
+
+		// choose the size of the whole permutation
+		weights := make([]*big.Rat, size+1)
+		for k, c := range s.counts(root) {
+			weights[k] = new(big.Rat).SetInt(c)
+		}
+
+		k, ok := pickWeighted(weights, r)
+		if !ok {
+			return nil, &Error{
+				Message: fmt.Sprintf("there is no permutation with at most the size %d", size),
+				Type:    ErrNoPermutationWithinSize,
+			}
+		}
+
+		continueFuzzing := make(chan struct{})
+
+		go func() {
+			log.Debugf("start uniform random fuzzing routine with size %d", k)
+
+			s.fuzz(root, int(k), r)
+
+			fuzzYADDA(root, r)
+
+			log.Debug("done with fuzzing step")
+
+			// done with the last fuzzing step
+			continueFuzzing <- struct{}{}
+
+			log.Debug("finished fuzzing. Wait till the outside is ready to close.")
+
+			if _, ok := <-continueFuzzing; ok {
+				log.Debug("close fuzzing channel")
+
+				close(continueFuzzing)
+			}
+		}()
+
+		return continueFuzzing, nil
+	}
+}
+
+// NewBoltzmannRandom returns a fuzzing strategy that generates a random permutation of a token graph with respect to a Boltzmann distribution with the parameter x.
+// The size of a permutation is the number of its terminal tokens like with the UniformRandom strategy. Every permutation of the size n is generated with a probability proportional to x^n, which means that x < 1 favours small and x > 1 favours big permutations. The parameter 1 results in the same distribution as the UniformRandom strategy. The strategy does exactly one iteration.
+func NewBoltzmannRandom(x float64) Strategy {
+	if x <= 0 {
+		panic("Boltzmann parameter must be positive")
+	}
+
+	return func(root token.Token, r rand.Rand) (chan struct{}, error) {
+		b := &boltzmann{
+			x:          new(big.Rat).SetFloat64(x),
+			structures: newStructures(),
+			memo:       make(map[int]*big.Rat),
+		}
+
+		return newUniformRandom(root, r, b.generating)
+	}
+}
+
+func newUniformRandom(root token.Token, r rand.Rand, weight func(tok token.Token) *big.Rat) (chan struct{}, error) {
+	if r == nil {
+		return nil, &Error{
+			Message: "random generator is nil",
+			Type:    ErrNilRandomGenerator,
+		}
+	}
+
+	if token.LoopExists(root) {
+		return nil, &Error{
+			Message: "found endless loop in graph. Cannot proceed.",
+			Type:    ErrEndlessLoopDetected,
+		}
+	}
+
+	s := &uniformRandom{
+		root:   root,
+		weight: weight,
+	}
+
+	continueFuzzing := make(chan struct{})
+
+	go func() {
+		log.Debug("start uniform random fuzzing routine")
+
+		s.fuzz(s.root, r, token.NewVariableScope())
+
+		fuzzYADDA(s.root, r)
+
+		log.Debug("done with fuzzing step")
+
+		// done with the last fuzzing step
+		continueFuzzing <- struct{}{}
+
+		log.Debug("finished fuzzing. Wait till the outside is ready to close.")
+
+		if _, ok := <-continueFuzzing; ok {
+			log.Debug("close fuzzing channel")
+
+			close(continueFuzzing)
+		}
+	}()
+
+	return continueFuzzing, nil
+}
+
+func (s *uniformRandom) fuzz(tok token.Token, r rand.Rand, variableScope *token.VariableScope) {
+	log.Debugf("Fuzz (%p)%#v with maxPermutations %d", tok, tok, tok.Permutations())
+
+	if t, ok := tok.(token.Scoping); ok && t.Scoping() {
+		variableScope = variableScope.Push()
+	}
+
+	i, ok := s.permutation(tok, r)
+	if !ok {
+		i = randomPermutation(tok, r)
+	}
+
+	err := tok.Permutation(i)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	for _, c := range uniformChildren(tok) {
+		s.fuzz(c, r, variableScope)
+	}
+
+	if t, ok := tok.(token.Scoping); ok && t.Scoping() {
+		variableScope = variableScope.Pop()
+	}
+}
+
+// permutation chooses a permutation of the token with respect to the weights of its children. False is returned if the token has no children which can be weighted.
+func (s *uniformRandom) permutation(tok token.Token, r rand.Rand) (uint, bool) {
+	if !hasUniformChildren(tok) {
+		return 0, false
+	}
+
+	weights := make([]*big.Rat, tok.Permutations())
+	for i := range weights {
+		if err := tok.Permutation(uint(i)); err != nil {
+			log.Panic(err)
+		}
+
+		weights[i] = s.childrenWeight(tok)
+	}
+
+	return pickWeighted(weights, r)
+}
+
+func (s *uniformRandom) childrenWeight(tok token.Token) *big.Rat {
+	w := big.NewRat(1, 1)
+
+	for _, c := range uniformChildren(tok) {
+		w.Mul(w, s.weight(c))
+	}
+
+	return w
+}
+
+type uniformRandomSize struct {
+	size       int
+	structures *structures
+
+	// memo holds the number of permutations of every size for every structure key
+	memo map[int][]*big.Int
+}
+
+// counts returns the number of permutations of the given token including its children for every size up to the size bound
+func (s *uniformRandomSize) counts(tok token.Token) []*big.Int {
+	return s.countsKey(s.structures.key(tok))
+}
+
+func (s *uniformRandomSize) countsKey(k int) []*big.Int {
+	if c, ok := s.memo[k]; ok {
+		return c
+	}
+
+	st := s.structures.structures[k]
+
+	c := s.zero()
+
+	if st.children == nil {
+		c[1].Set(st.permutations)
+	} else {
+		for _, children := range st.children {
+			cs := make([][]*big.Int, len(children))
+			for j, ck := range children {
+				cs[j] = s.countsKey(ck)
+			}
+
+			for k, n := range s.product(cs) {
+				c[k].Add(c[k], n)
+			}
+		}
+	}
+
+	s.memo[k] = c
+
+	return c
+}
+
+// childrenCounts returns the number of permutations of the given tokens together for every size up to the size bound
+func (s *uniformRandomSize) childrenCounts(children []token.Token) []*big.Int {
+	cs := make([][]*big.Int, len(children))
+	for i, c := range children {
+		cs[i] = s.counts(c)
+	}
+
+	return s.product(cs)
+}
+
+// product returns the number of combinations of the given counts for every size up to the size bound
+func (s *uniformRandomSize) product(counts [][]*big.Int) []*big.Int {
+	c := s.zero()
+	c[0].SetInt64(1)
+
+	for _, cc := range counts {
+		n := s.zero()
+		for i := 0; i <= s.size; i++ {
+			if c[i].Sign() == 0 {
+				continue
+			}
+
+			for j := 0; i+j <= s.size; j++ {
+				n[i+j].Add(n[i+j], new(big.Int).Mul(c[i], cc[j]))
+			}
+		}
+
+		c = n
+	}
+
+	return c
+}
+
+func (s *uniformRandomSize) zero() []*big.Int {
+	c := make([]*big.Int, s.size+1)
+	for i := range c {
+		c[i] = new(big.Int)
+	}
+
+	return c
+}
+
+// fuzz chooses a permutation of the given token including its children with exactly the given size
+func (s *uniformRandomSize) fuzz(tok token.Token, size int, r rand.Rand) {
+	log.Debugf("Fuzz (%p)%#v with size %d", tok, tok, size)
+
+	if !hasUniformChildren(tok) {
+		if err := tok.Permutation(randomPermutation(tok, r)); err != nil {
+			log.Panic(err)
+		}
+
+		return
+	}
+
+	weights := make([]*big.Rat, tok.Permutations())
+	for i := range weights {
+		if err := tok.Permutation(uint(i)); err != nil {
+			log.Panic(err)
+		}
+
+		weights[i] = new(big.Rat).SetInt(s.childrenCounts(uniformChildren(tok))[size])
+	}
+
+	i, ok := pickWeighted(weights, r)
+	if !ok {
+		panic("there is no permutation with the chosen size")
+	}
+
+	if err := tok.Permutation(i); err != nil {
+		log.Panic(err)
+	}
+
+	// distribute the size over the children
+	children := uniformChildren(tok)
+	remaining := size
+
+	for j, c := range children {
+		rest := s.childrenCounts(children[j+1:])
+		cc := s.counts(c)
+
+		weights := make([]*big.Rat, remaining+1)
+		for k := range weights {
+			weights[k] = new(big.Rat).SetInt(new(big.Int).Mul(cc[k], rest[remaining-k]))
+		}
+
+		k, ok := pickWeighted(weights, r)
+		if !ok {
+			panic("there is no permutation with the chosen size")
+		}
+
+		s.fuzz(c, int(k), r)
+
+		remaining -= int(k)
+	}
+}
+
+type boltzmann struct {
+	x          *big.Rat
+	structures *structures
+
+	// memo holds the value of the generating function for every structure key
+	memo map[int]*big.Rat
+}
+
+// generating returns the value of the generating function of the given token for the Boltzmann parameter
+func (b *boltzmann) generating(tok token.Token) *big.Rat {
+	return b.generatingKey(b.structures.key(tok))
+}
+
+func (b *boltzmann) generatingKey(k int) *big.Rat {
+	if g, ok := b.memo[k]; ok {
+		return g
+	}
+
+	st := b.structures.structures[k]
+
+	g := new(big.Rat)
+
+	if st.children == nil {
+		g.SetInt(st.permutations)
+		g.Mul(g, b.x)
+	} else {
+		for _, children := range st.children {
+			w := big.NewRat(1, 1)
+			for _, ck := range children {
+				w.Mul(w, b.generatingKey(ck))
+			}
+
+			g.Add(g, w)
+		}
+	}
+
+	b.memo[k] = g
+
+	return g
+}
+
+// structure holds the structure of a token which determines the number of its permutations
+type structure struct {
+	// children holds the structure keys of the traversed children for every permutation of the token and is nil for terminal tokens
+	children [][]int
+	// permutations holds the number of permutations of a terminal token
+	permutations *big.Int
+}
+
+// structures assigns the same key to tokens with the same structure, e.g. the clones of a repeated token, so that their permutations are only counted once
+type structures struct {
+	keys       map[token.Token]int
+	lookup     map[string]int
+	structures []structure
+}
+
+func newStructures() *structures {
+	return &structures{
+		keys:   make(map[token.Token]int),
+		lookup: make(map[string]int),
+	}
+}
+
+// key returns the structure key of the given token
+func (s *structures) key(tok token.Token) int {
+	if k, ok := s.keys[tok]; ok {
+		return k
+	}
+
+	var st structure
+	var id string
+
+	if hasUniformChildren(tok) {
+		st.children = make([][]int, tok.Permutations())
+		for i := range st.children {
+			if err := tok.Permutation(uint(i)); err != nil {
+				log.Panic(err)
+			}
+
+			st.children[i] = []int{}
+			for _, c := range uniformChildren(tok) {
+				st.children[i] = append(st.children[i], s.key(c))
+			}
+		}
+
+		id = fmt.Sprint(st.children)
+	} else {
+		st.permutations = tok.PermutationsBig()
+
+		id = "terminal " + st.permutations.String()
+	}
+
+	k, ok := s.lookup[id]
+	if !ok {
+		k = len(s.structures)
+
+		s.lookup[id] = k
+		s.structures = append(s.structures, st)
+	}
+
+	s.keys[tok] = k
+
+	return k
+}
+
+// hasUniformChildren returns true if the token can have children which are traversed
+func hasUniformChildren(tok token.Token) bool {
+	if t, ok := tok.(token.Follow); ok && !t.Follow() {
+		return false
+	}
+
+	switch tok.(type) {
+	case token.ForwardToken, token.ListToken:
+		return true
+	}
+
+	return false
+}
+
+// uniformChildren returns the children of the token which are traversed for its current permutation
+func uniformChildren(tok token.Token) []token.Token {
+	if !hasUniformChildren(tok) {
+		return nil
+	}
+
+	var children []token.Token
+
+	switch t := tok.(type) {
+	case token.ForwardToken:
+		if v := t.Get(); v != nil {
+			children = append(children, v)
+		}
+	case token.ListToken:
+		l := t.Len()
+
+		for i := 0; i < l; i++ {
+			c, _ := t.Get(i)
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
+
+// pickWeighted returns a random index with respect to the given weights. False is returned if all weights are zero.
+func pickWeighted(weights []*big.Rat, r rand.Rand) (uint, bool) {
+	sum := new(big.Rat)
+	for _, w := range weights {
+		sum.Add(sum, w)
+	}
+
+	if sum.Sign() == 0 {
+		return 0, false
+	}
+
+	n := new(big.Rat).SetFrac(randomBigInt(sum.Num(), r), sum.Denom())
+
+	for i, w := range weights {
+		if n.Cmp(w) < 0 {
+			return uint(i), true
+		}
+
+		n.Sub(n, w)
+	}
+
+	panic("unreachable")
+}
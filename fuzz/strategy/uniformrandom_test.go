@@ -0,0 +1,178 @@
+package strategy
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token"
+)
+
+func TestUniformRandomStrategyNilRandomGenerator(t *testing.T) {
+	ch, err := NewUniformRandom(nil, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+
+	ch, err = NewUniformRandomWithSize(10)(nil, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+
+	ch, err = NewBoltzmannRandom(1)(nil, nil)
+	Nil(t, ch)
+	Equal(t, ErrNilRandomGenerator, err.(*Error).Type)
+}
+
+func TestUniformRandomStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = *("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	// every repetition is weighted by its number of permutations, which are 1 + 3 + 9
+	s := &uniformRandom{
+		weight: func(tok token.Token) *big.Rat {
+			return new(big.Rat).SetInt(tok.PermutationsAllBig())
+		},
+	}
+	Equal(t, big.NewRat(13, 1), s.childrenWeight(root))
+
+	rep := root.(token.ForwardToken).InternalGet()
+	for i, w := range []int64{1, 3, 9} {
+		Nil(t, rep.Permutation(uint(i)))
+		Equal(t, big.NewRat(w, 1), s.childrenWeight(rep))
+	}
+
+	got := sampleStrategy(t, NewUniformRandom, root, 130)
+	Equal(t, 13, len(got))
+
+	// the random strategy favours short outputs
+	got = sampleStrategy(t, NewRandom, root, 130)
+	True(t, got[""] > 30)
+}
+
+func TestUniformRandomStrategyWithSize(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = *("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	// the size is the number of terminal tokens
+	s := &uniformRandomSize{
+		size:       3,
+		structures: newStructures(),
+		memo:       make(map[int][]*big.Int),
+	}
+	Equal(t, []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(9), big.NewInt(0)}, s.counts(root))
+
+	// the clones of the repeated token share their structure
+	rep := root.(token.ForwardToken).InternalGet()
+	Nil(t, rep.Permutation(2))
+	a, _ := rep.(token.ListToken).Get(0)
+	b, _ := rep.(token.ListToken).Get(1)
+	True(t, a != b)
+	Equal(t, s.structures.key(a), s.structures.key(b))
+
+	got := sampleStrategy(t, NewUniformRandomWithSize(1), root, 40)
+	Equal(t, 4, len(got))
+	for s := range got {
+		True(t, len(s) < 2, s)
+	}
+
+	got = sampleStrategy(t, NewUniformRandomWithSize(2), root, 130)
+	Equal(t, 13, len(got))
+
+	// there is no permutation which is small enough
+	root, err = parser.ParseTavor(strings.NewReader(`
+		START = +2("a")
+	`))
+	Nil(t, err)
+
+	ch, err := NewUniformRandomWithSize(1)(root, rand.New(rand.NewSource(1)))
+	Nil(t, ch)
+	Equal(t, ErrNoPermutationWithinSize, err.(*Error).Type)
+}
+
+func TestUniformRandomStrategyOptions(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = *("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	// the size option must not be negative
+	_, err = NewWithOptions("UniformRandom", map[string]string{"size": "-1"})
+	NotNil(t, err)
+
+	strat, err := NewWithOptions("UniformRandom", map[string]string{"size": "1"})
+	Nil(t, err)
+	got := sampleStrategy(t, strat, root, 40)
+	Equal(t, 4, len(got))
+}
+
+func TestBoltzmannRandomStrategy(t *testing.T) {
+	root, err := parser.ParseTavor(strings.NewReader(`
+		START = *("a" | "b" | "c")
+	`))
+	Nil(t, err)
+
+	// every terminal token is weighted by the parameter
+	for x, g := range map[float64]*big.Rat{
+		1:   big.NewRat(13, 1),
+		0.5: big.NewRat(19, 4),
+		2:   big.NewRat(43, 1),
+	} {
+		b := &boltzmann{
+			x:          new(big.Rat).SetFloat64(x),
+			structures: newStructures(),
+			memo:       make(map[int]*big.Rat),
+		}
+		Equal(t, g, b.generating(root))
+	}
+
+	// the parameter 1 is equal to the uniform distribution
+	got := sampleStrategy(t, NewBoltzmannRandom(1), root, 130)
+	Equal(t, 13, len(got))
+
+	// small parameters favour small outputs
+	got = sampleStrategy(t, NewBoltzmannRandom(0.1), root, 100)
+	True(t, got[""] > 60)
+
+	// big parameters favour big outputs
+	got = sampleStrategy(t, NewBoltzmannRandom(10), root, 100)
+	long := 0
+	for s, n := range got {
+		if len(s) == 2 {
+			long += n
+		}
+	}
+	True(t, long > 80)
+}
+
+func TestUniformRandomStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewUniformRandom)
+	testStrategyLoopDetection(t, NewUniformRandomWithSize(10))
+	testStrategyLoopDetection(t, NewBoltzmannRandom(0.5))
+}
+
+func sampleStrategy(t *testing.T, strat Strategy, root token.Token, n int) map[string]int {
+	r := rand.New(rand.NewSource(1))
+
+	got := make(map[string]int)
+
+	for i := 0; i < n; i++ {
+		ch, err := strat(root, r)
+		Nil(t, err)
+
+		_, ok := <-ch
+		True(t, ok)
+
+		got[root.String()]++
+
+		close(ch)
+	}
+
+	return got
+}
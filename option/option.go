@@ -0,0 +1,200 @@
+package option
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrorType the option error type
+type ErrorType int
+
+const (
+	// ErrInvalidSpecification the specification could not be split into a name and arguments
+	ErrInvalidSpecification ErrorType = iota
+	// ErrUnknownOption the option is not declared
+	ErrUnknownOption
+	// ErrInvalidValue the value does not match the type of the option or is out of its range
+	ErrInvalidValue
+)
+
+// Error holds an option error
+type Error struct {
+	Message string
+	Type    ErrorType
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Type defines the type of an option value
+type Type int
+
+const (
+	// Int the option value is an integer
+	Int Type = iota
+	// Float the option value is a floating-point number
+	Float
+	// Bool the option value is a boolean
+	Bool
+	// String the option value is a string
+	String
+)
+
+func (t Type) String() string {
+	switch t {
+	case Int:
+		return "int"
+	case Float:
+		return "float"
+	case Bool:
+		return "bool"
+	case String:
+		return "string"
+	}
+
+	return "unknown"
+}
+
+// Option declares an option of a registered instance like a fuzzing strategy
+type Option struct {
+	Name        string
+	Type        Type
+	Default     string
+	Description string
+}
+
+func (o Option) String() string {
+	return fmt.Sprintf("%s=%s (%s) %s", o.Name, o.Default, o.Type, o.Description)
+}
+
+// parse parses the given value with respect to the type of the option
+func (o Option) parse(value string) (interface{}, error) {
+	var v interface{}
+	var err error
+
+	switch o.Type {
+	case Int:
+		v, err = strconv.Atoi(value)
+	case Float:
+		v, err = strconv.ParseFloat(value, 64)
+	case Bool:
+		v, err = strconv.ParseBool(value)
+	case String:
+		v = value
+	default:
+		panic(fmt.Sprintf("unknown option type %d", o.Type))
+	}
+
+	if err != nil {
+		return nil, &Error{
+			Message: fmt.Sprintf("value %q of option %q is not of type %s", value, o.Name, o.Type),
+			Type:    ErrInvalidValue,
+		}
+	}
+
+	return v, nil
+}
+
+// Values holds the typed values of options
+type Values map[string]interface{}
+
+// Int returns the value of the given integer option
+func (v Values) Int(name string) int {
+	return v.get(name).(int)
+}
+
+// Float returns the value of the given floating-point option
+func (v Values) Float(name string) float64 {
+	return v.get(name).(float64)
+}
+
+// Bool returns the value of the given boolean option
+func (v Values) Bool(name string) bool {
+	return v.get(name).(bool)
+}
+
+// String returns the value of the given string option
+func (v Values) String(name string) string {
+	return v.get(name).(string)
+}
+
+func (v Values) get(name string) interface{} {
+	value, ok := v[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown option %q", name))
+	}
+
+	return value
+}
+
+// Split splits a specification like "name:key=value,key=value" into its name and its arguments
+func Split(specification string) (string, map[string]string, error) {
+	i := strings.Index(specification, ":")
+	if i == -1 {
+		return specification, nil, nil
+	}
+
+	name := specification[:i]
+	arguments := make(map[string]string)
+
+	if rest := specification[i+1:]; rest != "" {
+		for _, a := range strings.Split(rest, ",") {
+			kv := strings.SplitN(a, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return "", nil, &Error{
+					Message: fmt.Sprintf("argument %q of %q is not of the form key=value", a, name),
+					Type:    ErrInvalidSpecification,
+				}
+			}
+
+			arguments[kv[0]] = kv[1]
+		}
+	}
+
+	return name, arguments, nil
+}
+
+// Parse parses the given arguments with respect to the given declared options.
+// Options which are not given as argument have their default value. The error return argument is not nil, if an argument is not declared or does not match the type of its option.
+func Parse(options []Option, arguments map[string]string) (Values, error) {
+	values := make(Values, len(options))
+
+	declared := make(map[string]struct{}, len(options))
+	for _, o := range options {
+		declared[o.Name] = struct{}{}
+	}
+
+	var names []string
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := declared[name]; !ok {
+			return nil, &Error{
+				Message: fmt.Sprintf("unknown option %q", name),
+				Type:    ErrUnknownOption,
+			}
+		}
+	}
+
+	for _, o := range options {
+		value, ok := arguments[o.Name]
+		if !ok {
+			value = o.Default
+		}
+
+		v, err := o.parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		values[o.Name] = v
+	}
+
+	return values, nil
+}
@@ -0,0 +1,77 @@
+package option
+
+import (
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+)
+
+func TestSplit(t *testing.T) {
+	name, arguments, err := Split("random")
+	Nil(t, err)
+	Equal(t, "random", name)
+	Nil(t, arguments)
+
+	name, arguments, err = Split("random:")
+	Nil(t, err)
+	Equal(t, "random", name)
+	Equal(t, map[string]string{}, arguments)
+
+	name, arguments, err = Split("random:count=1000,name=a=b")
+	Nil(t, err)
+	Equal(t, "random", name)
+	Equal(t, map[string]string{
+		"count": "1000",
+		"name":  "a=b",
+	}, arguments)
+
+	_, _, err = Split("random:count")
+	Equal(t, ErrInvalidSpecification, err.(*Error).Type)
+
+	_, _, err = Split("random:=1")
+	Equal(t, ErrInvalidSpecification, err.(*Error).Type)
+}
+
+func TestParse(t *testing.T) {
+	options := []Option{
+		{Name: "count", Type: Int, Default: "1"},
+		{Name: "x", Type: Float, Default: "0.5"},
+		{Name: "verbose", Type: Bool, Default: "false"},
+		{Name: "name", Type: String, Default: "tavor"},
+	}
+
+	values, err := Parse(options, nil)
+	Nil(t, err)
+	Equal(t, 1, values.Int("count"))
+	Equal(t, 0.5, values.Float("x"))
+	Equal(t, false, values.Bool("verbose"))
+	Equal(t, "tavor", values.String("name"))
+
+	values, err = Parse(options, map[string]string{
+		"count":   "1000",
+		"x":       "2",
+		"verbose": "true",
+		"name":    "woodpecker",
+	})
+	Nil(t, err)
+	Equal(t, 1000, values.Int("count"))
+	Equal(t, 2.0, values.Float("x"))
+	Equal(t, true, values.Bool("verbose"))
+	Equal(t, "woodpecker", values.String("name"))
+
+	Panics(t, func() {
+		values.Int("unknown")
+	})
+
+	_, err = Parse(options, map[string]string{
+		"unknown": "1",
+	})
+	Equal(t, ErrUnknownOption, err.(*Error).Type)
+
+	_, err = Parse(options, map[string]string{
+		"count": "a lot",
+	})
+	Equal(t, ErrInvalidValue, err.(*Error).Type)
+
+	Equal(t, "count=1 (int) ", options[0].String())
+}
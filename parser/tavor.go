@@ -2,8 +2,10 @@ package parser
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"strconv"
@@ -50,7 +52,35 @@ type call struct {
 	variableScope *token.VariableScope
 }
 
+type function struct {
+	name       string
+	parameters []string
+	position   scanner.Position
+
+	// the body is kept as source and parsed anew for every call
+	bodyStart int
+	bodyEnd   int
+
+	used bool
+}
+
+type functionArgument struct {
+	name     string
+	token    token.Token
+	position scanner.Position
+}
+
+type functionCall struct {
+	name           string
+	arguments      []functionArgument
+	position       scanner.Position
+	pointer        *primitives.Pointer
+	definitionName string
+	variableScope  *token.VariableScope
+}
+
 type tavorParser struct {
+	data []byte
 	scan scanner.Scanner
 
 	err string
@@ -64,6 +94,20 @@ type tavorParser struct {
 	called map[string][]call
 
 	forwardAttributeUsage []attributeForwardUsage
+
+	functions     map[string]*function
+	functionCalls []functionCall
+	arguments     []map[string]token.Token
+	expanding     map[string]struct{}
+}
+
+func (p *tavorParser) initScanner(src io.Reader) {
+	p.scan.Init(src)
+
+	p.scan.Error = func(s *scanner.Scanner, msg string) {
+		p.err = msg
+	}
+	p.scan.Whitespace = 1<<'\t' | 1<<' ' | 1<<'\r'
 }
 
 func (p *tavorParser) expectRune(expect rune, got rune) (rune, error) {
@@ -160,6 +204,17 @@ func (p *tavorParser) parseGlobalScope(variableScope *token.VariableScope) error
 }
 
 func (p *tavorParser) getToken(definitionName string, name string, variableScope *token.VariableScope) token.Token {
+	if n := len(p.arguments); n != 0 {
+		if tok, ok := p.arguments[n-1][name]; ok {
+			// every usage of a parameter gets its own copy of the argument
+			tok = tok.Clone()
+
+			log.Debugf("use function argument %s as (%p)%#v", name, tok, tok)
+
+			return tok
+		}
+	}
+
 	if tok := variableScope.Get(name); tok != nil {
 		if v, ok := tok.(token.VariableToken); ok {
 			tok = variables.NewVariableValue(v)
@@ -294,6 +349,50 @@ func (p *tavorParser) addCall(definitionName string, variableScope *token.Variab
 	p.called[name] = append(p.called[name], c)
 }
 
+func (p *tavorParser) parseConstantInteger() token.Token {
+	s := p.scan.TokenText()
+
+	// hexadecimal integers are byte literals e.g. 0x7f is the single byte 127
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+		if len(s)%2 == 1 {
+			s = "0" + s
+		}
+
+		b, _ := hex.DecodeString(s)
+
+		return primitives.NewConstantString(string(b))
+	}
+
+	v, _ := strconv.Atoi(s)
+
+	return primitives.NewConstantInt(v)
+}
+
+func (p *tavorParser) parseConstantString() (token.Token, error) {
+	s := p.scan.TokenText()
+
+	if s[len(s)-1] != '"' {
+		return nil, &token.ParserError{
+			Message:  "string is not terminated",
+			Type:     token.ParseErrorNonTerminatedString,
+			Position: p.scan.Pos(),
+		}
+	}
+
+	s, _ = strconv.Unquote(s)
+
+	if len(s) == 0 {
+		return nil, &token.ParserError{
+			Message:  "empty strings are not allowed",
+			Type:     token.ParseErrorEmptyString,
+			Position: p.scan.Pos(),
+		}
+	}
+
+	return primitives.NewConstantString(s), nil
+}
+
 func (p *tavorParser) parseTerm(definitionName string, c rune, variableScope *token.VariableScope) (rune, []token.Token, error) {
 	var err error
 	var tokens []token.Token
@@ -308,36 +407,30 @@ OUT:
 		case scanner.Ident:
 			name := p.scan.TokenText()
 
+			if p.scan.Peek() == '(' {
+				tok, err := p.parseFunctionCall(definitionName, name, variableScope)
+				if err != nil {
+					return zeroRune, nil, err
+				}
+
+				addToken(tok)
+
+				break
+			}
+
 			variableScope = variableScope.Push()
 			tok := p.getToken(definitionName, name, variableScope)
 
 			addToken(tok)
 		case scanner.Int:
-			v, _ := strconv.Atoi(p.scan.TokenText())
-
-			addToken(primitives.NewConstantInt(v))
+			addToken(p.parseConstantInteger())
 		case scanner.String:
-			s := p.scan.TokenText()
-
-			if s[len(s)-1] != '"' {
-				return zeroRune, nil, &token.ParserError{
-					Message:  "string is not terminated",
-					Type:     token.ParseErrorNonTerminatedString,
-					Position: p.scan.Pos(),
-				}
-			}
-
-			s, _ = strconv.Unquote(s)
-
-			if len(s) == 0 {
-				return zeroRune, nil, &token.ParserError{
-					Message:  "empty strings are not allowed",
-					Type:     token.ParseErrorEmptyString,
-					Position: p.scan.Pos(),
-				}
+			tok, err := p.parseConstantString()
+			if err != nil {
+				return zeroRune, nil, err
 			}
 
-			addToken(primitives.NewConstantString(s))
+			addToken(tok)
 		case '(':
 			log.Debug("Group:")
 			log.IncreaseIndentation()
@@ -1152,6 +1245,11 @@ func (p *tavorParser) selectTokenAttribute(definitionName string, tok token.Toke
 		case "Value":
 			return c, i.Clone(), nil
 		}
+	case *primitives.BinaryInt:
+		switch attribute {
+		case "Value":
+			return c, i.Clone(), nil
+		}
 	case token.VariableToken:
 		switch attribute {
 		case "Count":
@@ -1197,12 +1295,41 @@ func (p *tavorParser) selectTokenAttribute(definitionName string, tok token.Toke
 	}
 }
 
+func (p *tavorParser) parseWeight(c rune) (rune, int, error) {
+	if c != scanner.Int || p.scan.Peek() != ':' {
+		return c, 0, nil
+	}
+
+	weight, err := strconv.Atoi(p.scan.TokenText())
+	if err != nil || weight < 1 {
+		return zeroRune, 0, &token.ParserError{
+			Message:  fmt.Sprintf("weight %q must be a positive integer", p.scan.TokenText()),
+			Type:     token.ParseErrorInvalidArgumentValue,
+			Position: p.scan.Position,
+		}
+	}
+
+	log.Debugf("parseWeight %d", weight)
+
+	// skip the ':'
+	p.scan.Scan()
+
+	return p.scan.Scan(), weight, nil
+}
+
 func (p *tavorParser) parseScope(definitionName string, c rune, variableScope *token.VariableScope) (rune, []token.Token, error) {
 	var err error
 	var tokens []token.Token
 
 	var toks []token.Token
 
+	weightPosition := p.scan.Position
+
+	c, weight, err := p.parseWeight(c)
+	if err != nil {
+		return zeroRune, nil, err
+	}
+
 	c, toks, err = p.parseTerm(definitionName, c, variableScope)
 	if err != nil {
 		return zeroRune, nil, err
@@ -1210,6 +1337,14 @@ func (p *tavorParser) parseScope(definitionName string, c rune, variableScope *t
 		tokens = toks
 	}
 
+	if weight != 0 && c != '|' {
+		return zeroRune, nil, &token.ParserError{
+			Message:  "weights are only allowed for alternatives",
+			Type:     token.ParseErrorInvalidArgumentValue,
+			Position: weightPosition,
+		}
+	}
+
 	var ifPairs []conditions.IfPair
 
 SCOPE:
@@ -1220,6 +1355,8 @@ SCOPE:
 			log.IncreaseIndentation()
 
 			var orTerms []token.Token
+			var weights []int
+			weighted := false
 			optional := false
 
 			toks = tokens
@@ -1228,6 +1365,14 @@ SCOPE:
 			for {
 				switch len(toks) {
 				case 0:
+					if weight != 0 {
+						return zeroRune, nil, &token.ParserError{
+							Message:  "weights are not allowed for empty alternatives",
+							Type:     token.ParseErrorInvalidArgumentValue,
+							Position: weightPosition,
+						}
+					}
+
 					optional = true
 				case 1:
 					orTerms = append(orTerms, toks[0])
@@ -1235,6 +1380,15 @@ SCOPE:
 					orTerms = append(orTerms, lists.NewAll(toks...))
 				}
 
+				if len(toks) != 0 {
+					if weight == 0 {
+						weights = append(weights, 1)
+					} else {
+						weights = append(weights, weight)
+						weighted = true
+					}
+				}
+
 				if c == '|' {
 					c = p.scan.Scan()
 					log.Debugf("parseScope Or %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
@@ -1244,6 +1398,13 @@ SCOPE:
 					break OR
 				}
 
+				weightPosition = p.scan.Position
+
+				c, weight, err = p.parseWeight(c)
+				if err != nil {
+					return zeroRune, nil, err
+				}
+
 				c, toks, err = p.parseTerm(definitionName, c, variableScope)
 				if err != nil {
 					return zeroRune, nil, err
@@ -1252,6 +1413,12 @@ SCOPE:
 
 			or := lists.NewOne(orTerms...)
 
+			if weighted {
+				if err := or.SetWeights(weights); err != nil {
+					return zeroRune, nil, err
+				}
+			}
+
 			if optional {
 				tokens = []token.Token{constraints.NewOptional(or)}
 			} else {
@@ -1417,9 +1584,20 @@ func (p *tavorParser) parseTokenDefinition(variableScope *token.VariableScope) (
 			}
 		}
 	}
+	if _, ok := p.functions[name]; ok {
+		return zeroRune, &token.ParserError{
+			Message:  "token already defined",
+			Type:     token.ParseErrorTokenAlreadyDefined,
+			Position: p.scan.Pos(),
+		}
+	}
 
 	tokenPosition := p.scan.Position
 
+	if p.scan.Peek() == '(' {
+		return p.parseFunctionDefinition(name, tokenPosition)
+	}
+
 	if c, err = p.expectScanRune('='); err != nil {
 		// unexpected new line?
 		if c == '\n' {
@@ -1513,7 +1691,7 @@ func (p *tavorParser) setEarlyUsage(name string, tok token.Token) error {
 }
 
 func (p *tavorParser) registerNamedToken(name string, tok token.Token, tokenPosition scanner.Position, variableScope *token.VariableScope) error {
-	sTok := primitives.NewScope(tok)
+	sTok := primitives.NewNamedScope(name, tok)
 
 	err := p.setEarlyUsage(name, sTok)
 	if err != nil {
@@ -1531,6 +1709,344 @@ func (p *tavorParser) registerNamedToken(name string, tok token.Token, tokenPosi
 	return nil
 }
 
+func (p *tavorParser) parseFunctionDefinition(name string, position scanner.Position) (c rune, err error) {
+	log.Debugf("Function %s:", name)
+	log.IncreaseIndentation()
+	defer log.DecreaseIndentation()
+
+	f := &function{
+		name:     name,
+		position: position,
+	}
+
+	if _, err = p.expectScanRune('('); err != nil {
+		return zeroRune, err
+	}
+
+	c = p.scan.Scan()
+	log.Debugf("parseFunctionDefinition after ( %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+	for c != ')' {
+		if _, err = p.expectRune(scanner.Ident, c); err != nil {
+			return zeroRune, err
+		}
+
+		parameter := p.scan.TokenText()
+
+		for _, pa := range f.parameters {
+			if pa == parameter {
+				return zeroRune, &token.ParserError{
+					Message:  fmt.Sprintf("parameter %q already defined", parameter),
+					Type:     token.ParseErrorTokenAlreadyDefined,
+					Position: p.scan.Pos(),
+				}
+			}
+		}
+
+		f.parameters = append(f.parameters, parameter)
+
+		c = p.scan.Scan()
+		log.Debugf("parseFunctionDefinition after parameter %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+		if c == ',' {
+			c = p.scan.Scan()
+		} else if _, err = p.expectRune(')', c); err != nil {
+			return zeroRune, err
+		}
+	}
+
+	if c, err = p.expectScanRune('='); err != nil {
+		return zeroRune, err
+	}
+
+	// the body is parsed for every call of the function so we just have to know where it is
+	f.bodyStart = p.scan.Pos().Offset
+
+	c = p.scan.Scan()
+
+	if c == '\n' {
+		return zeroRune, &token.ParserError{
+			Message:  "empty token definition",
+			Type:     token.ParseErrorEmptyTokenDefinition,
+			Position: p.scan.Pos(),
+		}
+	}
+
+	for c != '\n' && c != scanner.EOF {
+		if c == ',' {
+			// multi line token
+			if c = p.scan.Scan(); c == '\n' {
+				c = p.scan.Scan()
+			}
+
+			continue
+		}
+
+		c = p.scan.Scan()
+	}
+
+	// we always want a new line at the end of the file
+	if c == scanner.EOF {
+		return zeroRune, &token.ParserError{
+			Message:  "new line at end of token definition needed",
+			Type:     token.ParseErrorNewLineNeeded,
+			Position: p.scan.Pos(),
+		}
+	}
+
+	f.bodyEnd = p.scan.Position.Offset
+
+	p.functions[name] = f
+
+	log.Debugf("added function %s with parameters %v", name, f.parameters)
+
+	c = p.scan.Scan()
+	log.Debugf("parseFunctionDefinition after newline %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+	return c, nil
+}
+
+func (p *tavorParser) parseFunctionCall(definitionName string, name string, variableScope *token.VariableScope) (token.Token, error) {
+	position := p.scan.Position
+
+	log.Debugf("Call of function %s:", name)
+	log.IncreaseIndentation()
+	defer log.DecreaseIndentation()
+
+	if _, err := p.expectScanRune('('); err != nil {
+		return nil, err
+	}
+
+	arguments, err := p.parseFunctionArguments(definitionName, variableScope)
+	if err != nil {
+		return nil, err
+	}
+
+	call := functionCall{
+		name:           name,
+		arguments:      arguments,
+		position:       position,
+		definitionName: definitionName,
+		variableScope:  variableScope,
+	}
+
+	if _, ok := p.functions[name]; !ok {
+		// the function could be defined later on so we have to expand the call after the whole format is parsed
+		var tokenInterface *token.Token
+		call.pointer = primitives.NewEmptyPointer(tokenInterface)
+
+		p.functionCalls = append(p.functionCalls, call)
+
+		return call.pointer, nil
+	}
+
+	return p.expandFunctionCall(call)
+}
+
+func (p *tavorParser) parseFunctionArguments(definitionName string, variableScope *token.VariableScope) ([]functionArgument, error) {
+	var arguments []functionArgument
+
+	c := p.scan.Scan()
+	log.Debugf("parseFunctionArguments after ( %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+	for c != ')' {
+		argument := functionArgument{
+			position: p.scan.Position,
+		}
+
+		if c == scanner.Ident && p.scan.Peek() == ':' {
+			argument.name = p.scan.TokenText()
+
+			if _, err := p.expectScanRune(':'); err != nil {
+				return nil, err
+			}
+
+			c = p.scan.Scan()
+		}
+
+		switch c {
+		case scanner.Ident:
+			name := p.scan.TokenText()
+
+			if p.scan.Peek() == '(' {
+				tok, err := p.parseFunctionCall(definitionName, name, variableScope)
+				if err != nil {
+					return nil, err
+				}
+
+				argument.token = tok
+			} else {
+				argument.token = p.getToken(definitionName, name, variableScope)
+			}
+		case scanner.Int:
+			argument.token = p.parseConstantInteger()
+		case scanner.String:
+			tok, err := p.parseConstantString()
+			if err != nil {
+				return nil, err
+			}
+
+			argument.token = tok
+		default:
+			return nil, &token.ParserError{
+				Message:  fmt.Sprintf("invalid function argument %s", scanner.TokenString(c)),
+				Type:     token.ParseErrorInvalidArgumentValue,
+				Position: p.scan.Pos(),
+			}
+		}
+
+		arguments = append(arguments, argument)
+
+		c = p.scan.Scan()
+		log.Debugf("parseFunctionArguments after argument %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+		if c == ',' {
+			c = p.scan.Scan()
+		} else if _, err := p.expectRune(')', c); err != nil {
+			return nil, err
+		}
+	}
+
+	return arguments, nil
+}
+
+func (f *function) bind(call functionCall) (map[string]token.Token, error) {
+	arguments := make(map[string]token.Token, len(f.parameters))
+
+	for i, argument := range call.arguments {
+		name := argument.name
+
+		if name == "" {
+			if i >= len(f.parameters) {
+				return nil, &token.ParserError{
+					Message:  fmt.Sprintf("function %q expects %d arguments but got %d", f.name, len(f.parameters), len(call.arguments)),
+					Type:     token.ParseErrorFunctionArgumentCount,
+					Position: call.position,
+				}
+			}
+
+			name = f.parameters[i]
+		} else {
+			known := false
+
+			for _, pa := range f.parameters {
+				if pa == name {
+					known = true
+
+					break
+				}
+			}
+
+			if !known {
+				return nil, &token.ParserError{
+					Message:  fmt.Sprintf("function %q has no parameter %q", f.name, name),
+					Type:     token.ParseErrorUnknownFunctionParameter,
+					Position: argument.position,
+				}
+			}
+		}
+
+		if _, ok := arguments[name]; ok {
+			return nil, &token.ParserError{
+				Message:  fmt.Sprintf("parameter %q of function %q is set more than once", name, f.name),
+				Type:     token.ParseErrorFunctionArgumentCount,
+				Position: argument.position,
+			}
+		}
+
+		arguments[name] = argument.token
+	}
+
+	if len(arguments) != len(f.parameters) {
+		return nil, &token.ParserError{
+			Message:  fmt.Sprintf("function %q expects %d arguments but got %d", f.name, len(f.parameters), len(call.arguments)),
+			Type:     token.ParseErrorFunctionArgumentCount,
+			Position: call.position,
+		}
+	}
+
+	return arguments, nil
+}
+
+func (p *tavorParser) expandFunctionCall(call functionCall) (token.Token, error) {
+	f, ok := p.functions[call.name]
+	if !ok {
+		return nil, &token.ParserError{
+			Message:  fmt.Sprintf("function %q is not defined", call.name),
+			Type:     token.ParseErrorFunctionNotDefined,
+			Position: call.position,
+		}
+	}
+
+	if _, ok := p.expanding[f.name]; ok {
+		return nil, &token.ParserError{
+			Message:  fmt.Sprintf("function %q calls itself", f.name),
+			Type:     token.ParseErrEndlessLoopDetected,
+			Position: call.position,
+		}
+	}
+
+	arguments, err := f.bind(call)
+	if err != nil {
+		return nil, err
+	}
+
+	f.used = true
+
+	log.Debugf("Expand function %s:", f.name)
+	log.IncreaseIndentation()
+	defer log.DecreaseIndentation()
+
+	p.addCall(call.definitionName, call.variableScope, f.name)
+
+	p.expanding[f.name] = struct{}{}
+	p.arguments = append(p.arguments, arguments)
+	scan := p.scan
+
+	// scan the original source again so positions stay the same
+	p.initScanner(bytes.NewReader(p.data[:f.bodyEnd]))
+	for p.scan.Pos().Offset < f.bodyStart {
+		p.scan.Scan()
+	}
+
+	c := p.scan.Scan()
+	log.Debugf("expandFunctionCall %d:%v -> %v", p.scan.Line, scanner.TokenString(c), p.scan.TokenText())
+
+	c, tokens, err := p.parseScope(f.name, c, call.variableScope.Push())
+	if err == nil && c != scanner.EOF {
+		err = &token.ParserError{
+			Message:  fmt.Sprintf("unexpected %s in function body", scanner.TokenString(c)),
+			Type:     token.ParseErrorUnexpectedTokenDefinitionTermination,
+			Position: p.scan.Pos(),
+		}
+	}
+
+	p.scan = scan
+	p.arguments = p.arguments[:len(p.arguments)-1]
+	delete(p.expanding, f.name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tok token.Token
+
+	switch len(tokens) {
+	case 0:
+		return nil, &token.ParserError{
+			Message:  "empty token definition",
+			Type:     token.ParseErrorEmptyTokenDefinition,
+			Position: f.position,
+		}
+	case 1:
+		tok = tokens[0]
+	default:
+		tok = lists.NewAll(tokens...)
+	}
+
+	return primitives.NewNamedScope(f.name, tok), nil
+}
+
 func (p *tavorParser) parseTypedTokenDefinition(variableScope *token.VariableScope) (rune, error) {
 	var c rune
 	var err error
@@ -1739,16 +2255,22 @@ func ParseTavor(src io.Reader) (token.Token, error) {
 		used:        make(map[string][]tokenUsage),
 
 		called: make(map[string][]call),
+
+		functions: make(map[string]*function),
+		expanding: make(map[string]struct{}),
 	}
 
 	log.Debug("start parsing tavor file")
 
-	p.scan.Init(src)
-
-	p.scan.Error = func(s *scanner.Scanner, msg string) {
-		p.err = msg
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
 	}
-	p.scan.Whitespace = 1<<'\t' | 1<<' ' | 1<<'\r'
+
+	// the source is kept since bodies of functions are parsed for every call
+	p.data = data
+
+	p.initScanner(bytes.NewReader(data))
 
 	variableScope := token.NewVariableScope()
 
@@ -1756,6 +2278,17 @@ func ParseTavor(src io.Reader) (token.Token, error) {
 		return nil, err
 	}
 
+	for _, call := range p.functionCalls {
+		tok, err := p.expandFunctionCall(call)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := call.pointer.Set(tok); err != nil {
+			return nil, err
+		}
+	}
+
 	if _, ok := p.lookup["START"]; !ok {
 		return nil, &token.ParserError{
 			Message:  "no START token defined",
@@ -1879,6 +2412,16 @@ func ParseTavor(src io.Reader) (token.Token, error) {
 		}
 	}
 
+	for name, f := range p.functions {
+		if !f.used {
+			return nil, &token.ParserError{
+				Message:  fmt.Sprintf("function %q declared but not used", name),
+				Type:     token.ParseErrorUnusedToken,
+				Position: f.position,
+			}
+		}
+	}
+
 	for _, variable := range p.variableUsages {
 		tok := variable.(token.ForwardToken).InternalGet()
 
@@ -1923,7 +2466,11 @@ func ParseTavor(src io.Reader) (token.Token, error) {
 		start = lists.NewAll(automaticResets...)
 	}
 
-	start, err := token.UnrollPointers(start)
+	if tavor.UnrollLoops {
+		start, err = token.UnrollPointers(start)
+	} else {
+		start, err = replaceLoopPointers(start)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1939,3 +2486,98 @@ func ParseTavor(src io.Reader) (token.Token, error) {
 
 	return start, nil
 }
+
+// replaceLoopPointers replaces all pointers which lead back to themselves with loop tokens and unrolls all other pointers
+func replaceLoopPointers(root token.Token) (token.Token, error) {
+	origins := make(map[token.Token]struct{})
+	visited := make(map[token.Token]struct{})
+	stack := make(map[token.Token]struct{})
+
+	var replace func(tok token.Token) error
+	replace = func(tok token.Token) error {
+		if _, ok := visited[tok]; ok {
+			return nil
+		}
+
+		visited[tok] = struct{}{}
+		stack[tok] = struct{}{}
+		defer delete(stack, tok)
+
+		if t, ok := tok.(token.Follow); ok && !t.Follow() {
+			return nil
+		}
+
+		var children []token.Token
+
+		switch t := tok.(type) {
+		case token.ForwardToken:
+			if c := t.InternalGet(); c != nil {
+				children = append(children, c)
+			}
+		case token.ListToken:
+			for i := 0; i < t.InternalLen(); i++ {
+				c, _ := t.InternalGet(i)
+
+				children = append(children, c)
+			}
+		}
+
+		for _, c := range children {
+			if po, ok := c.(token.PointerToken); ok {
+				origin := po.InternalGet()
+				checked := map[token.Token]struct{}{
+					po: struct{}{},
+				}
+				for {
+					o, ok := origin.(token.PointerToken)
+					if !ok {
+						break
+					} else if _, found := checked[o]; found {
+						break
+					}
+
+					checked[o] = struct{}{}
+					origin = o.InternalGet()
+				}
+
+				if _, ok := stack[origin]; ok {
+					l := primitives.NewLoop(origin)
+
+					log.Debugf("replace pointer (%p)%#v in (%p)%#v with loop (%p)%#v", c, c, tok, tok, l, l)
+
+					if err := tok.(token.InternalReplace).InternalReplace(c, l); err != nil {
+						return err
+					}
+
+					origins[origin] = struct{}{}
+
+					continue
+				}
+			}
+
+			if err := replace(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := replace(root); err != nil {
+		return nil, err
+	}
+
+	root, err := token.UnrollPointers(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// loops clone their origins which must not contain pointers either
+	for origin := range origins {
+		if _, err := token.UnrollPointers(origin); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
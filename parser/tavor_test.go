@@ -261,6 +261,67 @@ func TestTavorParseErrors(t *testing.T) {
 		`))
 	Equal(t, token.ParseErrEndlessLoopDetected, err.(*token.ParserError).Type)
 	Nil(t, tok)
+
+	// function called with too few arguments
+	tok, err = ParseTavor(strings.NewReader("F(a, b) = a b\nSTART = F(1)\n"))
+	Equal(t, token.ParseErrorFunctionArgumentCount, err.(*token.ParserError).Type)
+	Equal(t, 2, err.(*token.ParserError).Position.Line)
+	Equal(t, 9, err.(*token.ParserError).Position.Column)
+	Nil(t, tok)
+
+	// function called with too many arguments
+	tok, err = ParseTavor(strings.NewReader("F(a) = a\nSTART = F(1, 2)\n"))
+	Equal(t, token.ParseErrorFunctionArgumentCount, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// function argument set twice
+	tok, err = ParseTavor(strings.NewReader("F(a, b) = a b\nSTART = F(1, a: 2)\n"))
+	Equal(t, token.ParseErrorFunctionArgumentCount, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// unknown function parameter
+	tok, err = ParseTavor(strings.NewReader("F(a) = a\nSTART = F(b: 1)\n"))
+	Equal(t, token.ParseErrorUnknownFunctionParameter, err.(*token.ParserError).Type)
+	Equal(t, 2, err.(*token.ParserError).Position.Line)
+	Equal(t, 11, err.(*token.ParserError).Position.Column)
+	Nil(t, tok)
+
+	// function is not defined
+	tok, err = ParseTavor(strings.NewReader("START = F(1)\n"))
+	Equal(t, token.ParseErrorFunctionNotDefined, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// token is not a function
+	tok, err = ParseTavor(strings.NewReader("A = 1\nSTART = A A(1)\n"))
+	Equal(t, token.ParseErrorFunctionNotDefined, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// function parameter already defined
+	tok, err = ParseTavor(strings.NewReader("F(a, a) = a\nSTART = F(1, 2)\n"))
+	Equal(t, token.ParseErrorTokenAlreadyDefined, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// function already defined as token
+	tok, err = ParseTavor(strings.NewReader("F = 1\nF(a) = a\nSTART = F F(1)\n"))
+	Equal(t, token.ParseErrorTokenAlreadyDefined, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// function is not used
+	tok, err = ParseTavor(strings.NewReader("F(a) = a\nSTART = 1\n"))
+	Equal(t, token.ParseErrorUnusedToken, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// function calls itself
+	tok, err = ParseTavor(strings.NewReader("F(a) = a | F(a)\nSTART = F(1)\n"))
+	Equal(t, token.ParseErrEndlessLoopDetected, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// errors in function bodies are reported with their position in the body
+	tok, err = ParseTavor(strings.NewReader("START = F(1)\nF(a) = a Unknown\n"))
+	Equal(t, token.ParseErrorTokenNotDefined, err.(*token.ParserError).Type)
+	Equal(t, 2, err.(*token.ParserError).Position.Line)
+	Equal(t, 10, err.(*token.ParserError).Position.Column)
+	Nil(t, tok)
 }
 
 func TestTavorParserSimple(t *testing.T) {
@@ -270,42 +331,42 @@ func TestTavorParserSimple(t *testing.T) {
 	// constant integer
 	tok, err = ParseTavor(strings.NewReader("START = 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantInt(123)))
 
 	// single line comment
 	tok, err = ParseTavor(strings.NewReader("// hello\nSTART = 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantInt(123)))
 
 	// single line multi line comment
 	tok, err = ParseTavor(strings.NewReader("/* hello */\nSTART = 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantInt(123)))
 
 	// multi line multi line comment
 	tok, err = ParseTavor(strings.NewReader("/*\nh\ne\nl\nl\no\n*/\nSTART = 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantInt(123)))
 
 	// inline comment
 	tok, err = ParseTavor(strings.NewReader("START /* ok */= /* or so */ 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantInt(123)))
 
 	// constant string
 	tok, err = ParseTavor(strings.NewReader("START = \"abc\"\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantString("abc")))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantString("abc")))
 
 	// constant string with whitespaces and epic chars
 	tok, err = ParseTavor(strings.NewReader("START = \"a b c !\\n\\\"$%&/\"\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantString("a b c !\n\"$%&/")))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewConstantString("a b c !\n\"$%&/")))
 
 	// concatination
 	tok, err = ParseTavor(strings.NewReader("START = \"I am a constant string\" 123\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantString("I am a constant string"),
 		primitives.NewConstantInt(123),
 	)))
@@ -313,17 +374,17 @@ func TestTavorParserSimple(t *testing.T) {
 	// embed token
 	tok, err = ParseTavor(strings.NewReader("Token=123\nSTART = Token\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Token"}, primitives.NewConstantInt(123)))
 
 	// embed over token
 	tok, err = ParseTavor(strings.NewReader("Token=123\nAnotherToken = Token\nSTART = AnotherToken\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "AnotherToken", "Token"}, primitives.NewConstantInt(123)))
 
 	// multi line token
 	tok, err = ParseTavor(strings.NewReader("START = 1,\n2,\n3\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 		primitives.NewConstantInt(3),
@@ -332,7 +393,7 @@ func TestTavorParserSimple(t *testing.T) {
 	// Umläüt
 	tok, err = ParseTavor(strings.NewReader("Umläüt=123\nSTART = Umläüt\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Umläüt"}, primitives.NewConstantInt(123)))
 }
 
 func TestTavorParserAlternationsAndGroupings(t *testing.T) {
@@ -342,7 +403,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple alternation
 	tok, err = ParseTavor(strings.NewReader("START = 1 | 2 | 3\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 		primitives.NewConstantInt(3),
@@ -351,7 +412,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// concatinated alternation
 	tok, err = ParseTavor(strings.NewReader("START = 1 | 2 3 | 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
 		primitives.NewConstantInt(1),
 		lists.NewAll(
 			primitives.NewConstantInt(2),
@@ -363,21 +424,21 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// optional alternation
 	tok, err = ParseTavor(strings.NewReader("START = | 2 | 3\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(constraints.NewOptional(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", constraints.NewOptional(lists.NewOne(
 		primitives.NewConstantInt(2),
 		primitives.NewConstantInt(3),
 	))))
 
 	tok, err = ParseTavor(strings.NewReader("START = 1 | | 3\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(constraints.NewOptional(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", constraints.NewOptional(lists.NewOne(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(3),
 	))))
 
 	tok, err = ParseTavor(strings.NewReader("START = 1 | 2 |\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(constraints.NewOptional(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", constraints.NewOptional(lists.NewOne(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 	))))
@@ -385,15 +446,15 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// alternation with embedded token
 	tok, err = ParseTavor(strings.NewReader("Token = 2\nSTART = 1 | Token\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
 		primitives.NewConstantInt(1),
-		primitives.NewScope(primitives.NewConstantInt(2)),
+		primitives.NewNamedScope("Token", primitives.NewConstantInt(2)),
 	)))
 
 	// simple group
 	tok, err = ParseTavor(strings.NewReader("START = (1 2 3)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 		primitives.NewConstantInt(3),
@@ -402,7 +463,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple embedded group
 	tok, err = ParseTavor(strings.NewReader("START = 0 (1 2 3) 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(0),
 		lists.NewAll(
 			primitives.NewConstantInt(1),
@@ -415,7 +476,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple embedded or group
 	tok, err = ParseTavor(strings.NewReader("START = 0 (1 | 2 | 3) 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(0),
 		lists.NewOne(
 			primitives.NewConstantInt(1),
@@ -428,7 +489,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// Yo dog, I heard you like groups? so here is a group in a group
 	tok, err = ParseTavor(strings.NewReader("START = (1 | (2 | 3)) | 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewOne(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
 		lists.NewOne(
 			primitives.NewConstantInt(1),
 			lists.NewOne(
@@ -442,7 +503,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple optional
 	tok, err = ParseTavor(strings.NewReader("START = 1 ?(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		constraints.NewOptional(primitives.NewConstantInt(2)),
 	)))
@@ -450,7 +511,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// or optional
 	tok, err = ParseTavor(strings.NewReader("START = 1 ?(2 | 3) 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		constraints.NewOptional(lists.NewOne(
 			primitives.NewConstantInt(2),
@@ -462,7 +523,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 1, int64(tavor.MaxRepeat)),
 	)))
@@ -470,7 +531,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// or repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +(2 | 3) 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(lists.NewOne(
 			primitives.NewConstantInt(2),
@@ -482,7 +543,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple optional repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 *(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 0, int64(tavor.MaxRepeat)),
 	)))
@@ -490,7 +551,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// or optional repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 *(2 | 3) 4\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(lists.NewOne(
 			primitives.NewConstantInt(2),
@@ -502,7 +563,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// simple optional repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 *(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 0, int64(tavor.MaxRepeat)),
 	)))
@@ -510,7 +571,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// exact repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +3(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 3, 3),
 	)))
@@ -518,7 +579,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// at least repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +3,(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 3, int64(tavor.MaxRepeat)),
 	)))
@@ -526,7 +587,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// at most repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +,3(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 1, 3),
 	)))
@@ -534,7 +595,7 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// range repeat
 	tok, err = ParseTavor(strings.NewReader("START = 1 +2,3(2)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 		primitives.NewConstantInt(1),
 		lists.NewRepeat(primitives.NewConstantInt(2), 2, 3),
 	)))
@@ -542,13 +603,150 @@ func TestTavorParserAlternationsAndGroupings(t *testing.T) {
 	// once list
 	tok, err = ParseTavor(strings.NewReader("START = @(1 | 2 | 3)\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewOnce(
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewOnce(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 		primitives.NewConstantInt(3),
 	)))
 }
 
+func TestTavorParserWeightedAlternations(t *testing.T) {
+	var tok token.Token
+	var err error
+
+	weighted := func(weights []int, toks ...token.Token) *lists.One {
+		o := lists.NewOne(toks...)
+		Nil(t, o.SetWeights(weights))
+
+		return o
+	}
+
+	// weighted alternation
+	tok, err = ParseTavor(strings.NewReader("START = 5: 1 | 1: 2\n"))
+	Nil(t, err)
+	Equal(t, tok, primitives.NewNamedScope("START", weighted([]int{5, 1},
+		primitives.NewConstantInt(1),
+		primitives.NewConstantInt(2),
+	)))
+
+	// alternatives without a weight have the weight 1
+	tok, err = ParseTavor(strings.NewReader("START = 1 2 | 3: 3 | 4\n"))
+	Nil(t, err)
+	Equal(t, tok, primitives.NewNamedScope("START", weighted([]int{1, 3, 1},
+		lists.NewAll(
+			primitives.NewConstantInt(1),
+			primitives.NewConstantInt(2),
+		),
+		primitives.NewConstantInt(3),
+		primitives.NewConstantInt(4),
+	)))
+
+	// no weights at all
+	tok, err = ParseTavor(strings.NewReader("START = 1 | 2\n"))
+	Nil(t, err)
+	Nil(t, tok.(*primitives.Scope).Get().(*lists.One).Weights())
+
+	// weighted alternation in a group
+	tok, err = ParseTavor(strings.NewReader("START = 1 (2: 2 | 3)\n"))
+	Nil(t, err)
+	Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+		primitives.NewConstantInt(1),
+		weighted([]int{2, 1},
+			primitives.NewConstantInt(2),
+			primitives.NewConstantInt(3),
+		),
+	)))
+
+	// weighted optional alternation
+	tok, err = ParseTavor(strings.NewReader("START = 2: 1 | | 2\n"))
+	Nil(t, err)
+	Equal(t, tok, primitives.NewNamedScope("START", constraints.NewOptional(weighted([]int{2, 1},
+		primitives.NewConstantInt(1),
+		primitives.NewConstantInt(2),
+	))))
+
+	// weights are only allowed for alternatives
+	tok, err = ParseTavor(strings.NewReader("START = 2: 1\n"))
+	Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// weights are not allowed for empty alternatives
+	tok, err = ParseTavor(strings.NewReader("START = 1 | 2: | 2\n"))
+	Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+	Nil(t, tok)
+
+	// weights must be positive
+	tok, err = ParseTavor(strings.NewReader("START = 0: 1 | 2\n"))
+	Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+	Nil(t, tok)
+}
+
+func TestTavorParserBinary(t *testing.T) {
+	// byte literals
+	{
+		tok, err := ParseTavor(strings.NewReader("START = 0x7f 0xCAFE 0x100 \"\\x00\\xff\"\n"))
+		Nil(t, err)
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewConstantString("\x7f"),
+			primitives.NewConstantString("\xca\xfe"),
+			primitives.NewConstantString("\x01\x00"),
+			primitives.NewConstantString("\x00\xff"),
+		)))
+	}
+	// binary typed tokens
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			$Length UInt16BE = from: 2,
+			                   to: 2
+			$Value Int32LE = from: -1,
+			                 to: -1
+			$Size VarInt = from: 300,
+			               to: 300
+
+			START = 0x01 Length Value Size
+		`))
+		Nil(t, err)
+
+		Equal(t, "\x01\x00\x02\xff\xff\xff\xff\xac\x02", tok.String())
+
+		errs := ParseInternal(tok, strings.NewReader("\x01\x00\x02\xff\xff\xff\xff\xac\x02"))
+		Nil(t, errs)
+
+		errs = ParseInternal(tok, strings.NewReader("\x01\x00\x03\xff\xff\xff\xff\xac\x02"))
+		Equal(t, token.ParseErrorUnexpectedData, errs[0].(*token.ParserError).Type)
+	}
+	// ranges of binary typed tokens are validated
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			$Byte UInt8 = to: 256
+
+			START = Byte
+		`))
+		Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+		Nil(t, tok)
+	}
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			$Size VarInt = from: 10,
+			               to: 5
+
+			START = Size
+		`))
+		Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+		Nil(t, tok)
+	}
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			$Length UInt16BE = from: 10,
+			                   to: 5
+
+			START = Length
+		`))
+		Equal(t, token.ParseErrorInvalidArgumentValue, err.(*token.ParserError).Type)
+		Nil(t, tok)
+	}
+}
+
 func TestTavorParserTokenAttributes(t *testing.T) {
 	// token attribute List.Count
 	{
@@ -562,8 +760,8 @@ func TestTavorParserTokenAttributes(t *testing.T) {
 		v, _ := tok.(*primitives.Scope).InternalGet().(*lists.All).Get(0)
 		list := v.(*primitives.Scope).InternalGet().(*lists.Repeat)
 
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			primitives.NewScope(lists.NewRepeat(primitives.NewScope(lists.NewOne(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("Digits", lists.NewRepeat(primitives.NewNamedScope("Digit", lists.NewOne(
 				primitives.NewConstantInt(1),
 				primitives.NewConstantInt(2),
 				primitives.NewConstantInt(3),
@@ -609,37 +807,37 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		"$Spec Int\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeInt(0, math.MaxInt32)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeInt(0, math.MaxInt32)))
 
 	tok, err = ParseTavor(strings.NewReader(
 		"$Spec Int = from: 2,\nto: 10\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeInt(2, 10)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeInt(2, 10)))
 
 	tok, err = ParseTavor(strings.NewReader(
 		"$Spec Int = from: 2,\nto: 10,\nstep: 2\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeIntWithStep(2, 10, 2)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeIntWithStep(2, 10, 2)))
 
 	tok, err = ParseTavor(strings.NewReader(
 		"$Spec Int = to: 10,\nstep: 2\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeIntWithStep(0, 10, 2)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeIntWithStep(0, 10, 2)))
 
 	tok, err = ParseTavor(strings.NewReader(
 		"$Spec Int = from: 2,\nstep: 2\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeIntWithStep(2, math.MaxInt32, 2)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeIntWithStep(2, math.MaxInt32, 2)))
 
 	tok, err = ParseTavor(strings.NewReader(
 		"$Spec Int = from: -10\nSTART = Spec\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeInt(-10, math.MaxInt32)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Spec"}, primitives.NewRangeInt(-10, math.MaxInt32)))
 
 	// Sequence
 	{
@@ -650,7 +848,7 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.Item()),
+			primitives.NewNamedScope("START", s.Item()),
 		))
 
 		s = sequences.NewSequence(2, 1)
@@ -660,7 +858,7 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.Item()),
+			primitives.NewNamedScope("START", s.Item()),
 		))
 
 		s = sequences.NewSequence(1, 3)
@@ -670,7 +868,7 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.Item()),
+			primitives.NewNamedScope("START", s.Item()),
 		))
 
 		s = sequences.NewSequence(1, 1)
@@ -680,7 +878,7 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.ExistingItem(nil)),
+			primitives.NewNamedScope("START", s.ExistingItem(nil)),
 		))
 
 		s = sequences.NewSequence(1, 1)
@@ -690,7 +888,7 @@ func TestTavorParserTypedTokens(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.ResetItem()),
+			primitives.NewNamedScope("START", s.ResetItem()),
 		))
 	}
 }
@@ -706,7 +904,7 @@ func TestTavorParserExpressions(t *testing.T) {
 			A = "a"
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(primitives.NewConstantString("a")))
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, primitives.NewConstantString("a")))
 	}
 
 	// variable use in expression
@@ -716,7 +914,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		`))
 		Nil(t, err)
 		v := variables.NewVariable("A", primitives.NewConstantString("a"))
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 			v,
 			variables.NewVariableValue(v),
 		)))
@@ -729,7 +927,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		`))
 		Nil(t, err)
 		v := variables.NewVariable("A", primitives.NewConstantString("a"))
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 			v,
 			variables.NewVariableValue(v),
 		)))
@@ -744,7 +942,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(s.Item()),
+			primitives.NewNamedScope("START", s.Item()),
 		))
 	}
 
@@ -753,7 +951,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		"START = ${1 + 2}\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewAddArithmetic(
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewAddArithmetic(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 	)))
@@ -764,9 +962,9 @@ func TestTavorParserExpressions(t *testing.T) {
 		B = 2
 	`))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewAddArithmetic(
-		primitives.NewScope(primitives.NewConstantInt(1)),
-		primitives.NewScope(primitives.NewConstantInt(2)),
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewAddArithmetic(
+		primitives.NewNamedScope("A", primitives.NewConstantInt(1)),
+		primitives.NewNamedScope("B", primitives.NewConstantInt(2)),
 	)))
 
 	// sub operator
@@ -774,7 +972,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		"START = ${1 - 2}\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewSubArithmetic(
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewSubArithmetic(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 	)))
@@ -784,7 +982,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		"START = ${1 * 2}\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewMulArithmetic(
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewMulArithmetic(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 	)))
@@ -794,7 +992,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		"START = ${1 / 2}\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewDivArithmetic(
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewDivArithmetic(
 		primitives.NewConstantInt(1),
 		primitives.NewConstantInt(2),
 	)))
@@ -804,7 +1002,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		"START = ${1 + 2 + 3}\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(expressions.NewAddArithmetic(
+	Equal(t, tok, primitives.NewNamedScope("START", expressions.NewAddArithmetic(
 		primitives.NewConstantInt(1),
 		expressions.NewAddArithmetic(
 			primitives.NewConstantInt(2),
@@ -821,7 +1019,7 @@ func TestTavorParserExpressions(t *testing.T) {
 		Nil(t, err)
 		Equal(t, tok, lists.NewAll(
 			s.ResetItem(),
-			primitives.NewScope(expressions.NewAddArithmetic(
+			primitives.NewNamedScope("START", expressions.NewAddArithmetic(
 				s.Item(),
 				primitives.NewConstantInt(1),
 			)),
@@ -856,14 +1054,14 @@ func TestTavorParserAndCuriousCaseOfFuzzing(t *testing.T) {
 		"START = Token\nToken = 123\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "Token"}, primitives.NewConstantInt(123)))
 
 	// double embedded forward token all the way
 	tok, err = ParseTavor(strings.NewReader("A = B B\nB = 1\nSTART = A\n"))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(lists.NewAll(
-		primitives.NewScope(primitives.NewConstantInt(1)),
-		primitives.NewScope(primitives.NewConstantInt(1)),
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
+		primitives.NewNamedScope("B", primitives.NewConstantInt(1)),
+		primitives.NewNamedScope("B", primitives.NewConstantInt(1)),
 	)))
 
 	// Token attribute forward usage
@@ -871,7 +1069,7 @@ func TestTavorParserAndCuriousCaseOfFuzzing(t *testing.T) {
 		"START = $int.Value\n$int Int\n",
 	))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewRangeInt(0, math.MaxInt32)))
+	Equal(t, tok, primitives.NewNamedScope("START", primitives.NewRangeInt(0, math.MaxInt32)))
 
 	// Tokens should be cloned so they are different internally
 	{
@@ -879,9 +1077,9 @@ func TestTavorParserAndCuriousCaseOfFuzzing(t *testing.T) {
 			"Token = 1 | 2\nSTART = Token Token\n",
 		))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			primitives.NewScope(lists.NewOne(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
-			primitives.NewScope(lists.NewOne(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("Token", lists.NewOne(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
+			primitives.NewNamedScope("Token", lists.NewOne(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
 		)))
 
 		va, _ := tok.(*primitives.Scope).InternalGet().(token.ListToken).Get(0)
@@ -1022,9 +1220,9 @@ func TestTavorParserLoops(t *testing.T) {
 	`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewOne(
-			primitives.NewScope(lists.NewOne(
-				primitives.NewScope(primitives.NewConstantInt(1)),
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewOne(
+			primitives.NewNamedScope("A", lists.NewOne(
+				primitives.NewNamedScope("A", primitives.NewConstantInt(1)),
 				primitives.NewConstantInt(1),
 			)),
 			primitives.NewConstantInt(1),
@@ -1040,11 +1238,11 @@ func TestTavorParserLoops(t *testing.T) {
 	`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewOne(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewOne(
 			lists.NewAll(
-				primitives.NewScope(lists.NewOne(
+				primitives.NewNamedScope("A", lists.NewOne(
 					lists.NewAll(
-						primitives.NewScope(primitives.NewConstantInt(2)),
+						primitives.NewNamedScope("A", primitives.NewConstantInt(2)),
 						primitives.NewConstantInt(1),
 					),
 					primitives.NewConstantInt(2),
@@ -1065,11 +1263,11 @@ func TestTavorParserLoops(t *testing.T) {
 	`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
 			constraints.NewOptional(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewNamedScope("A", lists.NewAll(
 					constraints.NewOptional(
-						primitives.NewScope(primitives.NewConstantInt(1)),
+						primitives.NewNamedScope("A", primitives.NewConstantInt(1)),
 					),
 					primitives.NewConstantInt(1),
 				)),
@@ -1088,11 +1286,11 @@ func TestTavorParserLoops(t *testing.T) {
 	`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
 			lists.NewOne(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewNamedScope("A", lists.NewAll(
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewNamedScope("A", lists.NewAll(
 							primitives.NewConstantInt(2),
 							primitives.NewConstantInt(1),
 						)),
@@ -1116,11 +1314,11 @@ func TestTavorParserLoops(t *testing.T) {
 			START = A
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
 			lists.NewOne(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewNamedScope("A", lists.NewAll(
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewNamedScope("A", lists.NewAll(
 							primitives.NewConstantInt(2),
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(3),
@@ -1129,7 +1327,7 @@ func TestTavorParserLoops(t *testing.T) {
 					),
 					primitives.NewConstantInt(1),
 					constraints.NewOptional(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewNamedScope("A", lists.NewAll(
 							primitives.NewConstantInt(2),
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(3),
@@ -1141,9 +1339,9 @@ func TestTavorParserLoops(t *testing.T) {
 			),
 			primitives.NewConstantInt(1),
 			constraints.NewOptional(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewNamedScope("A", lists.NewAll(
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewNamedScope("A", lists.NewAll(
 							primitives.NewConstantInt(2),
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(3),
@@ -1152,7 +1350,7 @@ func TestTavorParserLoops(t *testing.T) {
 					),
 					primitives.NewConstantInt(1),
 					constraints.NewOptional(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewNamedScope("A", lists.NewAll(
 							primitives.NewConstantInt(2),
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(3),
@@ -1178,11 +1376,11 @@ func TestTavorParserLoops(t *testing.T) {
 		`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
 			constraints.NewOptional(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewMultiNamedScope([]string{"C", "B", "A"}, lists.NewAll(
 					constraints.NewOptional(
-						primitives.NewScope(primitives.NewConstantInt(1)),
+						primitives.NewMultiNamedScope([]string{"C", "B", "A"}, primitives.NewConstantInt(1)),
 					),
 					primitives.NewConstantInt(1),
 				)),
@@ -1205,10 +1403,10 @@ func TestTavorParserLoops(t *testing.T) {
 		`))
 	Nil(t, err)
 	{
-		Equal(t, tok, primitives.NewScope(lists.NewRepeat(
-			primitives.NewScope(lists.NewOne(
-				primitives.NewScope(primitives.NewConstantString("setParam")),
-				primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewRepeat(
+			primitives.NewNamedScope("Action", lists.NewOne(
+				primitives.NewNamedScope("SetParameter", primitives.NewConstantString("setParam")),
+				primitives.NewNamedScope("GetParameter", lists.NewAll(
 					primitives.NewConstantString("getParam"),
 					lists.NewOne(
 						primitives.NewConstantString("param 1"),
@@ -1234,25 +1432,25 @@ func TestTavorParserLoops(t *testing.T) {
 			START = A
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "A"}, lists.NewAll(
 			lists.NewOne(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
 						primitives.NewConstantInt(1),
 					),
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
 						primitives.NewConstantInt(2),
 					),
 					constraints.NewOptional(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
@@ -1261,23 +1459,23 @@ func TestTavorParserLoops(t *testing.T) {
 				primitives.NewConstantInt(1),
 			),
 			lists.NewOne(
-				primitives.NewScope(lists.NewAll(
+				primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
 						primitives.NewConstantInt(1),
 					),
 					lists.NewOne(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
 						primitives.NewConstantInt(2),
 					),
 					constraints.NewOptional(
-						primitives.NewScope(lists.NewAll(
+						primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 							primitives.NewConstantInt(1),
 							primitives.NewConstantInt(2),
 						)),
@@ -1285,23 +1483,23 @@ func TestTavorParserLoops(t *testing.T) {
 				)),
 				primitives.NewConstantInt(2),
 			),
-			constraints.NewOptional(primitives.NewScope(lists.NewAll(
+			constraints.NewOptional(primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 				lists.NewOne(
-					primitives.NewScope(lists.NewAll(
+					primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 						primitives.NewConstantInt(1),
 						primitives.NewConstantInt(2),
 					)),
 					primitives.NewConstantInt(1),
 				),
 				lists.NewOne(
-					primitives.NewScope(lists.NewAll(
+					primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 						primitives.NewConstantInt(1),
 						primitives.NewConstantInt(2),
 					)),
 					primitives.NewConstantInt(2),
 				),
 				constraints.NewOptional(
-					primitives.NewScope(lists.NewAll(
+					primitives.NewMultiNamedScope([]string{"B", "C", "A"}, lists.NewAll(
 						primitives.NewConstantInt(1),
 						primitives.NewConstantInt(2),
 					)),
@@ -1313,6 +1511,141 @@ func TestTavorParserLoops(t *testing.T) {
 	}
 }
 
+func TestTavorParserLazyLoops(t *testing.T) {
+	tavor.UnrollLoops = false
+	defer func() {
+		tavor.UnrollLoops = true
+	}()
+
+	tok, err := ParseTavor(strings.NewReader(`
+		A = "(" A ")" | "x"
+
+		START = A
+	`))
+	Nil(t, err)
+	{
+		False(t, token.LoopExists(tok))
+
+		// loops are expanded on demand
+		Equal(t, "((x))", tok.String())
+
+		var got []string
+
+		ch, err := strategy.NewAllPermutations(tok, test.NewRandTest(1))
+		Nil(t, err)
+
+		for i := range ch {
+			got = append(got, tok.String())
+
+			ch <- i
+		}
+
+		Equal(t, []string{"((x))", "(x)", "x"}, got)
+
+		// internal parsing is not bound by the maximum of repetitions
+		errs := ParseInternal(tok, strings.NewReader("((((((x))))))"))
+		Nil(t, errs)
+		Equal(t, "((((((x))))))", tok.String())
+
+		errs = ParseInternal(tok, strings.NewReader("((((((x)))))"))
+		NotNil(t, errs)
+	}
+
+	tok, err = ParseTavor(strings.NewReader(`
+		Expr = Term "+" Expr | Term
+		Term = Factor "*" Term | Factor
+		Factor = "(" Expr ")" | 1
+
+		START = Expr
+	`))
+	Nil(t, err)
+	{
+		ch, err := strategy.NewRandom(tok, test.NewRandTest(1))
+		Nil(t, err)
+
+		_, ok := <-ch
+		True(t, ok)
+
+		out := tok.String()
+		Equal(t, strings.Count(out, "("), strings.Count(out, ")"))
+
+		close(ch)
+
+		errs := ParseInternal(tok, strings.NewReader("1+(1*((1+1)*1))*1"))
+		Nil(t, errs)
+		Equal(t, "1+(1*((1+1)*1))*1", tok.String())
+	}
+}
+
+func TestTavorParserFunctions(t *testing.T) {
+	// simple function
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			Field(Name, Value) = Name "=" Value "\n"
+
+			START = Field("a", 1) Field("b", 2)
+		`))
+		Nil(t, err)
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("Field", lists.NewAll(
+				primitives.NewConstantString("a"),
+				primitives.NewConstantString("="),
+				primitives.NewConstantInt(1),
+				primitives.NewConstantString("\n"),
+			)),
+			primitives.NewNamedScope("Field", lists.NewAll(
+				primitives.NewConstantString("b"),
+				primitives.NewConstantString("="),
+				primitives.NewConstantInt(2),
+				primitives.NewConstantString("\n"),
+			)),
+		)))
+
+		Equal(t, "a=1\nb=2\n", tok.String())
+	}
+	// function called before its definition with token references and named arguments
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			START = Field(Value: Number, Name: "a") Field("b", Number)
+
+			Number = 1 | 2
+
+			Field(Name, Value) = Name "=" Value ",",
+				"\n"
+		`))
+		Nil(t, err)
+
+		Equal(t, "a=1,\nb=1,\n", tok.String())
+		Equal(t, uint(4), tok.PermutationsAll())
+	}
+	// nested calls and parameters used more than once
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			Twice(Value) = Value Value
+			Pair(A, B) = "(" Twice(A) Twice(B) ")"
+
+			Digits = +2(2 | 3)
+
+			START = Pair(1, Digits)
+		`))
+		Nil(t, err)
+
+		Equal(t, "(112222)", tok.String())
+		Equal(t, uint(16), tok.PermutationsAll())
+	}
+	// variables as arguments
+	{
+		tok, err := ParseTavor(strings.NewReader(`
+			Print(Value) = "[" Value "]"
+
+			START = "a"<var> Print(var)
+		`))
+		Nil(t, err)
+
+		Equal(t, "a[a]", tok.String())
+	}
+}
+
 func TestTavorParserCornerCases(t *testing.T) {
 	// early usage used twice deeper in token
 	{
@@ -1327,9 +1660,9 @@ func TestTavorParserCornerCases(t *testing.T) {
 			START = a | b
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewOne(
-			primitives.NewScope(constraints.NewOptional(primitives.NewScope(primitives.NewConstantString("TEXT")))),
-			primitives.NewScope(constraints.NewOptional(primitives.NewScope(primitives.NewConstantString("TEXT")))),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
+			primitives.NewMultiNamedScope([]string{"a", "c"}, constraints.NewOptional(primitives.NewNamedScope("d", primitives.NewConstantString("TEXT")))),
+			primitives.NewMultiNamedScope([]string{"b", "c"}, constraints.NewOptional(primitives.NewNamedScope("d", primitives.NewConstantString("TEXT")))),
 		)))
 
 		Equal(t, "TEXT", tok.String())
@@ -1347,9 +1680,9 @@ func TestTavorParserCornerCases(t *testing.T) {
 			START = a | b
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewOne(
-			primitives.NewScope(primitives.NewConstantString("TEXT")),
-			primitives.NewScope(primitives.NewConstantString("TEXT")),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
+			primitives.NewMultiNamedScope([]string{"a", "c", "d"}, primitives.NewConstantString("TEXT")),
+			primitives.NewMultiNamedScope([]string{"b", "c", "d"}, primitives.NewConstantString("TEXT")),
 		)))
 
 		Equal(t, "TEXT", tok.String())
@@ -1367,9 +1700,9 @@ func TestTavorParserCornerCases(t *testing.T) {
 			START = a | b
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewOne(
-			primitives.NewScope(constraints.NewOptional(constraints.NewOptional(primitives.NewScope(primitives.NewConstantString("TEXT"))))),
-			primitives.NewScope(constraints.NewOptional(constraints.NewOptional(primitives.NewScope(primitives.NewConstantString("TEXT"))))),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewOne(
+			primitives.NewMultiNamedScope([]string{"a", "c"}, constraints.NewOptional(constraints.NewOptional(primitives.NewNamedScope("d", primitives.NewConstantString("TEXT"))))),
+			primitives.NewMultiNamedScope([]string{"b", "c"}, constraints.NewOptional(constraints.NewOptional(primitives.NewNamedScope("d", primitives.NewConstantString("TEXT"))))),
 		)))
 
 		Equal(t, "TEXT", tok.String())
@@ -1382,10 +1715,10 @@ func TestTavorParserCornerCases(t *testing.T) {
 			B = "B"
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			primitives.NewScope(primitives.NewConstantString("B")),
-			primitives.NewScope(primitives.NewConstantString("B")),
-			primitives.NewScope(primitives.NewConstantString("B")),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("B", primitives.NewConstantString("B")),
+			primitives.NewNamedScope("B", primitives.NewConstantString("B")),
+			primitives.NewNamedScope("B", primitives.NewConstantString("B")),
 		)))
 
 		Equal(t, "BBB", tok.String())
@@ -1397,10 +1730,10 @@ func TestTavorParserCornerCases(t *testing.T) {
 			B = 1 2
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			primitives.NewScope(lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
-			primitives.NewScope(lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
-			primitives.NewScope(lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("B", lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
+			primitives.NewNamedScope("B", lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
+			primitives.NewNamedScope("B", lists.NewAll(primitives.NewConstantInt(1), primitives.NewConstantInt(2))),
 		)))
 
 		Equal(t, "121212", tok.String())
@@ -1413,7 +1746,7 @@ func TestTavorParserCornerCases(t *testing.T) {
 				to: 1
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(primitives.NewRangeInt(1, 1)))
+		Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "V"}, primitives.NewRangeInt(1, 1)))
 
 		Equal(t, "1", tok.String())
 	}
@@ -1425,7 +1758,7 @@ func TestTavorParserCharacterClasses(t *testing.T) {
 			START = [123]
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(primitives.NewCharacterClass("123")))
+		Equal(t, tok, primitives.NewNamedScope("START", primitives.NewCharacterClass("123")))
 
 		Equal(t, "1", tok.String())
 	}
@@ -1434,7 +1767,7 @@ func TestTavorParserCharacterClasses(t *testing.T) {
 			START = [\w]
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(primitives.NewCharacterClass(`\w`)))
+		Equal(t, tok, primitives.NewNamedScope("START", primitives.NewCharacterClass(`\w`)))
 
 		Equal(t, "0", tok.String())
 	}
@@ -1444,7 +1777,7 @@ func TestTavorParserCharacterClasses(t *testing.T) {
 			START = [ ]
 		`))
 		Nil(t, err)
-		Equal(t, tok, primitives.NewScope(primitives.NewCharacterClass(` `)))
+		Equal(t, tok, primitives.NewNamedScope("START", primitives.NewCharacterClass(` `)))
 
 		Equal(t, " ", tok.String())
 	}
@@ -1461,10 +1794,10 @@ func TestTavorParserVariables(t *testing.T) {
 			Print = $var.Value
 		`))
 		Nil(t, err)
-		variable := variables.NewVariable("var", primitives.NewScope(primitives.NewConstantString("text")))
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		variable := variables.NewVariable("var", primitives.NewNamedScope("Save", primitives.NewConstantString("text")))
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 			variable,
-			primitives.NewScope(variables.NewVariableValue(variable)),
+			primitives.NewNamedScope("Print", variables.NewVariableValue(variable)),
 		)))
 
 		Equal(t, "texttext", tok.String())
@@ -1481,9 +1814,9 @@ func TestTavorParserVariables(t *testing.T) {
 		v1 := variables.NewVariable("var", primitives.NewConstantInt(1))
 		v2 := variables.NewVariable("var", primitives.NewConstantInt(2))
 
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			v1, primitives.NewScope(variables.NewVariableValue(v1)),
-			v2, primitives.NewScope(variables.NewVariableValue(v2)),
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			v1, primitives.NewNamedScope("Print", variables.NewVariableValue(v1)),
+			v2, primitives.NewNamedScope("Print", variables.NewVariableValue(v2)),
 		)))
 
 		Equal(t, "1122", tok.String())
@@ -1562,16 +1895,16 @@ func TestTavorParserIfElseIfElsedd(t *testing.T) {
 		variable, _ := tok.(*primitives.Scope).InternalGet().(*lists.All).InternalGet(0)
 		one := variable.(*variables.Variable).InternalGet().(*primitives.Scope).InternalGet()
 
-		nOne := primitives.NewScope(lists.NewOne(
+		nOne := primitives.NewNamedScope("Choose", lists.NewOne(
 			primitives.NewConstantInt(1),
 			primitives.NewConstantInt(2),
 			primitives.NewConstantInt(3),
 		))
 		nVariable := variables.NewVariable("var", nOne)
 
-		var ll token.Token = primitives.NewScope(lists.NewAll(
+		var ll token.Token = primitives.NewNamedScope("START", lists.NewAll(
 			nVariable,
-			primitives.NewScope(conditions.NewIf(
+			primitives.NewNamedScope("Print", conditions.NewIf(
 				conditions.IfPair{ // TODO FIXME AND FIXME!!!!!! allow unrolling of IfPairs and BooleanEquals and pretty much all in token/conditions
 					Head: conditions.NewBooleanEqual(primitives.NewPointer(primitives.NewTokenPointer(variables.NewVariableValue(nVariable))), primitives.NewConstantInt(1)),
 					Body: primitives.NewConstantString("var is one"),
@@ -1608,7 +1941,7 @@ func TestTavorParserIfElseIfElsedd(t *testing.T) {
 
 		nVariable := variables.NewVariable("var", primitives.NewConstantInt(1))
 
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
 			nVariable,
 			conditions.NewIf(
 				conditions.IfPair{
@@ -1642,10 +1975,10 @@ func TestTavorParserIfElseIfElsedd(t *testing.T) {
 
 		notDefinedScope := token.NewVariableScope().Push().Push()
 
-		Equal(t, tok, primitives.NewScope(lists.NewAll(
-			primitives.NewScope(lists.NewAll(
+		Equal(t, tok, primitives.NewNamedScope("START", lists.NewAll(
+			primitives.NewNamedScope("Token", lists.NewAll(
 				nVariable,
-				primitives.NewScope(conditions.NewIf(
+				primitives.NewNamedScope("Print", conditions.NewIf(
 					conditions.IfPair{
 						Head: conditions.NewVariableDefined("var", definedScope),
 						Body: primitives.NewConstantString("var is defined"),
@@ -1656,7 +1989,7 @@ func TestTavorParserIfElseIfElsedd(t *testing.T) {
 					},
 				)),
 			)),
-			primitives.NewScope(conditions.NewIf(
+			primitives.NewNamedScope("Print", conditions.NewIf(
 				conditions.IfPair{
 					Head: conditions.NewVariableDefined("var", notDefinedScope),
 					Body: primitives.NewConstantString("var is defined"),
@@ -1685,5 +2018,5 @@ func TestParseTavorExpressionOperatorInclude(t *testing.T) {
 
 	tok, err := ParseTavor(strings.NewReader(fmt.Sprintf("START = ${include %q}\n", tmpfile.Name())))
 	Nil(t, err)
-	Equal(t, tok, primitives.NewScope(primitives.NewConstantInt(123)))
+	Equal(t, tok, primitives.NewMultiNamedScope([]string{"START", "START"}, primitives.NewConstantInt(123)))
 }
@@ -0,0 +1,369 @@
+package strategy
+
+import (
+	"strconv"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+)
+
+func init() {
+	Register("DDMin", NewDDMin)
+}
+
+type ddminStrategy struct {
+	root token.Token
+
+	continueReducing chan struct{}
+	feedbackReducing <-chan ReduceFeedbackType
+}
+
+// NewDDMin implements a reduce strategy that reduces the data through the ddmin delta debugging algorithm of Zeller.
+// Every step of the strategy generates a new valid token graph state. The generation is deterministic. The deltas of the algorithm are the reducible tokens of the graph, e.g. optional tokens, and the items of lists with a variable repetition. A delta is either kept with its original value or removed, e.g. an optional token is deactivated and an item is removed from its list. Sets of deltas which would reduce a list below its minimum repetition are skipped. The algorithm first checks if all deltas can be removed at once. Otherwise the set of deltas is partitioned into two subsets. If a subset or the complement of a subset still results in a good feedback, the search continues with it. Otherwise the granularity of the partition is increased until every subset consists of exactly one delta. Sets of deltas which were already tested are not tested again. Each step uses the feedback to determine the next set of deltas.
+func NewDDMin(root token.Token) (chan struct{}, chan<- ReduceFeedbackType, error) {
+	if token.LoopExists(root) {
+		return nil, nil, &Error{
+			Message: "found endless loop in graph. Cannot proceed.",
+			Type:    ErrEndlessLoopDetected,
+		}
+	}
+
+	continueReducing := make(chan struct{})
+	feedbackReducing := make(chan ReduceFeedbackType)
+
+	s := &ddminStrategy{
+		root: root,
+
+		continueReducing: continueReducing,
+		feedbackReducing: feedbackReducing,
+	}
+
+	go func() {
+		log.Debug("start ddmin routine")
+
+		deltas := reducibleDeltas(s.root, true)
+
+		if len(deltas) > 0 {
+			log.Debug("start reducing step")
+
+			if _, contin := s.ddmin(deltas); !contin {
+				return
+			}
+		} else {
+			log.Debug("no reduceable tokens to begin with")
+		}
+
+		log.Debug("finished reducing")
+
+		close(continueReducing)
+		close(feedbackReducing)
+	}()
+
+	return continueReducing, feedbackReducing, nil
+}
+
+// ddminDelta is a delta of the ddmin algorithm
+type ddminDelta struct {
+	// tok is the reducible token of the delta
+	tok token.ReduceToken
+	// item is the index of the original item of a repeat token which is the delta, or -1 if the whole reducible token is the delta
+	item int
+	// children holds the tokens which are removed with the delta
+	children []token.Token
+}
+
+// ddmin reduces the given deltas and returns the deltas which have to be kept. False is returned if the channels are closed from outside.
+func (s *ddminStrategy) ddmin(deltas []*ddminDelta) ([]*ddminDelta, bool) {
+	index := make(map[*ddminDelta]int, len(deltas))
+	for i, d := range deltas {
+		index[d] = i
+	}
+
+	// the feedback of every tested set of deltas is cached, since the algorithm can test the same set more than once
+	tested := make(map[string]bool)
+
+	test := func(kept []*ddminDelta) (bool, bool) {
+		key := make([]byte, 0, 4*len(kept))
+		for _, d := range kept {
+			key = strconv.AppendInt(key, int64(index[d]), 10)
+			key = append(key, ',')
+		}
+
+		if good, ok := tested[string(key)]; ok {
+			return true, good
+		}
+
+		contin, good := s.test(deltas, kept)
+		if contin {
+			tested[string(key)] = good
+		}
+
+		return contin, good
+	}
+
+	// we always asume that the initial values are good so we start with removing all deltas
+	if contin, good := test(nil); !contin {
+		return nil, false
+	} else if good {
+		log.Debugf("removed all %d deltas", len(deltas))
+
+		return nil, true
+	}
+
+	c := deltas
+	n := 2
+
+	for len(c) >= 2 {
+		subsets := ddminSplit(c, n)
+		found := false
+
+		for _, subset := range subsets {
+			contin, good := test(subset)
+			if !contin {
+				return nil, false
+			} else if good {
+				c = subset
+				n = 2
+				found = true
+
+				break
+			}
+		}
+
+		// the complements equal the subsets for two subsets
+		if !found && n > 2 {
+			for i := range subsets {
+				var complement []*ddminDelta
+				for j, subset := range subsets {
+					if i != j {
+						complement = append(complement, subset...)
+					}
+				}
+
+				contin, good := test(complement)
+				if !contin {
+					return nil, false
+				} else if good {
+					c = complement
+					if n--; n < 2 {
+						n = 2
+					}
+					found = true
+
+					break
+				}
+			}
+		}
+
+		if found {
+			log.Debugf("reduced to %d of %d deltas", len(c), len(deltas))
+
+			continue
+		}
+
+		if n >= len(c) {
+			break
+		}
+
+		if n *= 2; n > len(c) {
+			n = len(c)
+		}
+	}
+
+	// restore the smallest good set of deltas
+	s.set(deltas, c)
+	resetReduced(s.root)
+
+	return c, true
+}
+
+// test sets the given deltas to the kept deltas and does a reduce step. It returns if the strategy can continue and if the feedback of the step was good. Sets of deltas which cannot be set are not good without doing a step.
+func (s *ddminStrategy) test(deltas []*ddminDelta, kept []*ddminDelta) (bool, bool) {
+	if !s.set(deltas, kept) {
+		log.Debug("deltas violate the minimum repetition of a list, skip them")
+
+		return true, false
+	}
+
+	contin, feedback := nextStep(s.root, s.continueReducing, s.feedbackReducing)
+
+	return contin, feedback == Good
+}
+
+// set keeps the original values of the kept deltas and reduces all other deltas to their minimum. False is returned and nothing is set if the kept items of a repeat are less than its minimum repetition.
+func (s *ddminStrategy) set(deltas []*ddminDelta, kept []*ddminDelta) bool {
+	keep := make(map[*ddminDelta]struct{}, len(kept))
+	for _, d := range kept {
+		keep[d] = struct{}{}
+	}
+
+	// collect the original and kept items of every repeat
+	var repeats []*lists.Repeat
+	items := make(map[*lists.Repeat]int)
+	keptItems := make(map[*lists.Repeat][]int)
+
+	for _, d := range deltas {
+		if d.item < 0 {
+			continue
+		}
+
+		r := d.tok.(*lists.Repeat)
+		if _, ok := items[r]; !ok {
+			repeats = append(repeats, r)
+		}
+
+		items[r]++
+		if _, ok := keep[d]; ok {
+			keptItems[r] = append(keptItems[r], d.item)
+		}
+	}
+
+	for _, r := range repeats {
+		if int64(len(keptItems[r])) < r.From() {
+			return false
+		}
+	}
+
+	for _, d := range deltas {
+		if d.item >= 0 {
+			continue
+		}
+
+		reduction := uint(0)
+		if _, ok := keep[d]; ok {
+			reduction = d.tok.Reduces() - 1
+		}
+
+		if err := d.tok.Reduce(reduction); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, r := range repeats {
+		if err := r.Reduce(repeatReduction(int(r.From()), items[r], keptItems[r])); err != nil {
+			panic(err)
+		}
+	}
+
+	return true
+}
+
+// repeatReduction returns the reduction of a repeat token with the given minimum repetition and number of original items which keeps exactly the given sorted original items.
+// The reductions of a repeat are ordered by the number of kept items and then lexicographically by the kept items.
+func repeatReduction(from int, n int, items []int) uint {
+	k := len(items)
+
+	var reduction uint
+	for j := from; j < k; j++ {
+		reduction += binomial(n, j)
+	}
+
+	previous := -1
+	for i, item := range items {
+		for v := previous + 1; v < item; v++ {
+			reduction += binomial(n-1-v, k-1-i)
+		}
+
+		previous = item
+	}
+
+	return reduction
+}
+
+// binomial returns the binomial coefficient of n over k
+func binomial(n int, k int) uint {
+	if k < 0 || k > n {
+		return 0
+	}
+
+	c := uint(1)
+	for i := 1; i <= k; i++ {
+		c = c * uint(n-k+i) / uint(i)
+	}
+
+	return c
+}
+
+// ddminSplit partitions the given deltas into n subsets of almost the same size
+func ddminSplit(deltas []*ddminDelta, n int) [][]*ddminDelta {
+	subsets := make([][]*ddminDelta, n)
+
+	for i := range subsets {
+		subsets[i] = deltas[i*len(deltas)/n : (i+1)*len(deltas)/n]
+	}
+
+	return subsets
+}
+
+// reducibleDeltas returns the deltas of the reducible tokens of the given token graph in depth-first order. Every item of a repeat token is its own delta. The deltas of the children of deltas are only searched if all is true.
+func reducibleDeltas(root token.Token, all bool) []*ddminDelta {
+	var deltas []*ddminDelta
+
+	var walk func(tok token.Token)
+	walk = func(tok token.Token) {
+		t, ok := tok.(token.ReduceToken)
+		if !ok || t.Reduces() < 2 {
+			for _, c := range reducibleChildren(tok) {
+				walk(c)
+			}
+
+			return
+		}
+
+		if r, ok := t.(*lists.Repeat); ok {
+			for i := 0; i < r.Len(); i++ {
+				c, _ := r.Get(i)
+
+				deltas = append(deltas, &ddminDelta{
+					tok:      r,
+					item:     i,
+					children: []token.Token{c},
+				})
+
+				if all {
+					walk(c)
+				}
+			}
+
+			return
+		}
+
+		children := reducibleChildren(tok)
+
+		deltas = append(deltas, &ddminDelta{
+			tok:      t,
+			item:     -1,
+			children: children,
+		})
+
+		if all {
+			for _, c := range children {
+				walk(c)
+			}
+		}
+	}
+
+	walk(root)
+
+	return deltas
+}
+
+// reducibleChildren returns the current children of the given token
+func reducibleChildren(tok token.Token) []token.Token {
+	switch t := tok.(type) {
+	case token.ForwardToken:
+		if c := t.Get(); c != nil {
+			return []token.Token{c}
+		}
+	case token.ListToken:
+		children := make([]token.Token, t.Len())
+		for i := range children {
+			children[i], _ = t.Get(i)
+		}
+
+		return children
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package strategy
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestDDMinStrategy(t *testing.T) {
+	{
+		root := primitives.NewConstantInt(1)
+
+		contin, _, err := NewDDMin(root)
+		Nil(t, err)
+
+		_, ok := <-contin
+		False(t, ok)
+
+		Equal(t, "1", root.String())
+	}
+	{
+		// Removing all deltas at once
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = ?("a") ?("b") "c"
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewDDMin,
+			tok,
+			"abc",
+			func(out string) ReduceFeedbackType {
+				return Good
+			},
+			[]string{
+				"c",
+			},
+			"c",
+		)
+	}
+	{
+		// Subsets, complements and increasing granularity
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = ?("a") ?("b") ?("c") ?("d")
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewDDMin,
+			tok,
+			"abcd",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "b") && strings.Contains(out, "d") {
+					return Good
+				}
+
+				return Bad
+			},
+			[]string{
+				"",
+				"ab",
+				"cd",
+				"a",
+				"b",
+				"c",
+				"d",
+				"bcd",
+				"bd",
+			},
+			"bd",
+		)
+	}
+	{
+		// Nested deltas and items of lists
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = A *(B)
+
+			A = ?("a")
+			B = "b" (C | )
+			C = "c"
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewDDMin,
+			tok,
+			"abcb",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "b") {
+					return Good
+				}
+
+				return Bad
+			},
+			nil,
+			"b",
+		)
+	}
+	{
+		// Inputs are never changed if they cannot be reduced
+		root := lists.NewRepeat(primitives.NewCharacterClass(`\w`), 10, 10)
+
+		validateTavorReduce(
+			t,
+			NewDDMin,
+			root,
+			"KrOxDOj4fU",
+			func(out string) ReduceFeedbackType {
+				return Good
+			},
+			nil,
+			"KrOxDOj4fU",
+		)
+	}
+}
+
+func TestRepeatReduction(t *testing.T) {
+	for _, from := range []int64{0, 1, 2} {
+		r := lists.NewRepeat(primitives.NewCharacterClass("0-9"), from, 5)
+		Nil(t, parser.ParseInternal(r, bytes.NewBufferString("01234")))
+
+		for i := uint(0); i < r.Reduces(); i++ {
+			Nil(t, r.Reduce(i))
+
+			var kept []int
+			for j := 0; j < r.Len(); j++ {
+				c, _ := r.Get(j)
+
+				k, _ := strconv.Atoi(c.String())
+				kept = append(kept, k)
+			}
+
+			Equal(t, i, repeatReduction(int(from), 5, kept), fmt.Sprintf("Reduction %d of %v", i, kept))
+		}
+	}
+}
+
+func TestDDMinStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewDDMin)
+}
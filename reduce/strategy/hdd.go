@@ -0,0 +1,66 @@
+package strategy
+
+import (
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/token"
+)
+
+func init() {
+	Register("HDD", NewHDD)
+}
+
+// NewHDD implements a reduce strategy that reduces the data through the hierarchical delta debugging algorithm of Misherghi and Su.
+// Every step of the strategy generates a new valid token graph state. The generation is deterministic. The token graph is reduced level by level from the root downwards. A level consists of the deltas of the DDMin strategy, e.g. optional tokens and the items of lists with a variable repetition, which are the nearest reducible descendants of the kept deltas of the level before. Each level is reduced with the ddmin algorithm of the DDMin strategy. Since whole subtrees are removed before their children are reduced, nested data usually needs less steps than with the DDMin strategy and lists with many items need less steps than with the Linear strategy. Each step uses the feedback to determine the next set of deltas.
+func NewHDD(root token.Token) (chan struct{}, chan<- ReduceFeedbackType, error) {
+	if token.LoopExists(root) {
+		return nil, nil, &Error{
+			Message: "found endless loop in graph. Cannot proceed.",
+			Type:    ErrEndlessLoopDetected,
+		}
+	}
+
+	continueReducing := make(chan struct{})
+	feedbackReducing := make(chan ReduceFeedbackType)
+
+	s := &ddminStrategy{
+		root: root,
+
+		continueReducing: continueReducing,
+		feedbackReducing: feedbackReducing,
+	}
+
+	go func() {
+		log.Debug("start hdd routine")
+
+		level := reducibleDeltas(s.root, false)
+
+		if len(level) == 0 {
+			log.Debug("no reduceable tokens to begin with")
+		}
+
+		for depth := 0; len(level) > 0; depth++ {
+			log.Debugf("reducing level %d with %d deltas", depth, len(level))
+
+			kept, contin := s.ddmin(level)
+			if !contin {
+				return
+			}
+
+			var next []*ddminDelta
+			for _, d := range kept {
+				for _, c := range d.children {
+					next = append(next, reducibleDeltas(c, false)...)
+				}
+			}
+
+			level = next
+		}
+
+		log.Debug("finished reducing")
+
+		close(continueReducing)
+		close(feedbackReducing)
+	}()
+
+	return continueReducing, feedbackReducing, nil
+}
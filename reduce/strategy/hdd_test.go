@@ -0,0 +1,109 @@
+package strategy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestHDDStrategy(t *testing.T) {
+	{
+		root := primitives.NewConstantInt(1)
+
+		contin, _, err := NewHDD(root)
+		Nil(t, err)
+
+		_, ok := <-contin
+		False(t, ok)
+
+		Equal(t, "1", root.String())
+	}
+	{
+		// Level by level
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = ?(A) ?(B)
+
+			A = "a" ?("1") ?("2")
+			B = "b" ?("3") ?("4")
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewHDD,
+			tok,
+			"a12b34",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "4") {
+					return Good
+				}
+
+				return Bad
+			},
+			[]string{
+				"",
+				"a12",
+				"b34",
+				"b",
+				"b3",
+				"b4",
+			},
+			"b4",
+		)
+	}
+}
+
+func TestHDDStrategyNeedsLessSteps(t *testing.T) {
+	format := `
+		START = +0,12(Group)
+
+		Group = "(" ?(Flag) +0,4(Item) ")"
+		Item = "[" ?(Flag) ?("x") "]"
+		Flag = "f"
+	`
+	input := "(f[x])(f[f])(f[x])(f[f])(f[x])(f[f])(f[x])(f[f])(f[x])(f[f])(f[x])(f[f])"
+
+	feedback := func(out string) ReduceFeedbackType {
+		if strings.Count(out, "x") == 6 {
+			return Good
+		}
+
+		return Bad
+	}
+
+	steps := func(newStrategy Strategy) int {
+		tok, err := parser.ParseTavor(bytes.NewBufferString(format))
+		Nil(t, err)
+
+		Nil(t, parser.ParseInternal(tok, bytes.NewBufferString(input)))
+
+		continueReducing, feedbackReducing, err := newStrategy(tok)
+		Nil(t, err)
+
+		n := 0
+		for i := range continueReducing {
+			n++
+
+			feedbackReducing <- feedback(tok.String())
+
+			continueReducing <- i
+		}
+
+		Equal(t, "([x])([x])([x])([x])([x])([x])", tok.String())
+
+		return n
+	}
+
+	hdd, ddmin, linear := steps(NewHDD), steps(NewDDMin), steps(NewLinear)
+	True(t, hdd < ddmin, "HDD needed %d steps, DDMin needed %d steps", hdd, ddmin)
+	True(t, hdd < linear, "HDD needed %d steps, Linear needed %d steps", hdd, linear)
+}
+
+func TestHDDStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewHDD)
+}
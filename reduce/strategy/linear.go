@@ -78,7 +78,7 @@ func (s *linearStrategy) reduce(continueReducing chan struct{}, feedbackReducing
 				panic(err)
 			}
 
-			contin, feedback := s.nextStep(continueReducing, feedbackReducing)
+			contin, feedback := nextStep(s.root, continueReducing, feedbackReducing)
 			if !contin {
 				return false
 			} else if feedback == Good {
@@ -113,38 +113,6 @@ func (s *linearStrategy) reduce(continueReducing chan struct{}, feedbackReducing
 	return true
 }
 
-func (s *linearStrategy) nextStep(continueReducing chan struct{}, feedbackReducing <-chan ReduceFeedbackType) (bool, ReduceFeedbackType) {
-	token.ResetScope(s.root)
-	token.ResetResetTokens(s.root)
-	token.ResetScope(s.root)
-
-	log.Debug("done with reducing step")
-
-	// done with this reduce step
-	continueReducing <- struct{}{}
-
-	// wait until we got feedback to the current state
-	feedback, ok := <-feedbackReducing
-	if ok {
-		log.Debugf("GOT FEEDBACK -> Looks %s", feedback)
-	} else {
-		log.Debug("reducing feedback channel closed from outside")
-
-		return false, Unknown
-	}
-
-	// wait until we are allowed to continue
-	if _, ok := <-continueReducing; !ok {
-		log.Debug("reducing continue channel closed from outside")
-
-		return false, Unknown
-	}
-
-	log.Debug("start reducing step")
-
-	return true, feedback
-}
-
 func (s *linearStrategy) getTree(root token.Token, fromChildren bool) []linearStrategyLevel {
 	var tree []linearStrategyLevel
 	var queue = linkedlist.New()
@@ -0,0 +1,238 @@
+package strategy
+
+import (
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func init() {
+	Register("MinimalDerivation", NewMinimalDerivation)
+}
+
+type minimalDerivationStrategy struct {
+	root token.Token
+
+	continueReducing chan struct{}
+	feedbackReducing <-chan ReduceFeedbackType
+
+	// reductions holds the current reduction of every token which was reduced by the strategy
+	reductions map[token.ReduceToken]uint
+	// bad holds the generations with a bad feedback, since nested subtrees can have the same smallest derivation
+	bad map[string]struct{}
+}
+
+// NewMinimalDerivation implements a reduce strategy that reduces the data by replacing subtrees with the smallest derivation of their definition.
+// Every step of the strategy generates a new valid token graph state. The generation is deterministic. The token graph is traversed from the root downwards. Every subtree is replaced by its smallest derivation, which means that reducible tokens, e.g. optional tokens and lists with a variable repetition, are reduced to their minimum, One tokens choose the alternative with the shortest smallest derivation, integer ranges choose their minimum and character classes choose their first character. Subtrees which already are their smallest derivation are skipped without a step. If the feedback of a replacement is good the subtree is kept as it is, otherwise the original subtree is restored, reducible tokens try their smaller reductions like with the Linear strategy, e.g. to remove the repetitions of a list which are not needed, and the replacement continues with its children. Tokens which can be minimized to a simpler token with token.Minimize are replaced by the smallest derivation of the simpler token. In contrast to the Linear strategy this allows for example a One token to switch from a long alternative to a short one. Each step uses the feedback to determine the next subtree.
+func NewMinimalDerivation(root token.Token) (chan struct{}, chan<- ReduceFeedbackType, error) {
+	if token.LoopExists(root) {
+		return nil, nil, &Error{
+			Message: "found endless loop in graph. Cannot proceed.",
+			Type:    ErrEndlessLoopDetected,
+		}
+	}
+
+	continueReducing := make(chan struct{})
+	feedbackReducing := make(chan ReduceFeedbackType)
+
+	s := &minimalDerivationStrategy{
+		root: root,
+
+		continueReducing: continueReducing,
+		feedbackReducing: feedbackReducing,
+
+		reductions: make(map[token.ReduceToken]uint),
+		bad:        make(map[string]struct{}),
+	}
+
+	go func() {
+		log.Debug("start minimal derivation routine")
+
+		if contin := s.reduce(s.root); !contin {
+			return
+		}
+
+		log.Debug("finished reducing")
+
+		close(continueReducing)
+		close(feedbackReducing)
+	}()
+
+	return continueReducing, feedbackReducing, nil
+}
+
+// reduce replaces the given subtree with its smallest derivation and continues with its children if the feedback is bad. False is returned if the channels are closed from outside.
+func (s *minimalDerivationStrategy) reduce(tok token.Token) bool {
+	var undo []func()
+
+	original := tok.String()
+
+	s.minimize(tok, &undo)
+
+	if tok.String() != original {
+		resetReduced(s.root)
+
+		generation := s.root.String()
+
+		if _, ok := s.bad[generation]; ok {
+			log.Debug("smallest derivation was already tested, skip it")
+		} else {
+			log.Debugf("replace (%p)%#v with its smallest derivation", tok, tok)
+
+			contin, feedback := nextStep(s.root, s.continueReducing, s.feedbackReducing)
+			if !contin {
+				return false
+			} else if feedback == Good {
+				return true
+			}
+
+			s.bad[generation] = struct{}{}
+		}
+	}
+
+	// restore the original subtree
+	for i := len(undo) - 1; i >= 0; i-- {
+		undo[i]()
+	}
+	resetReduced(s.root)
+
+	if t, ok := tok.(token.ReduceToken); ok {
+		if contin := s.reduceToken(t); !contin {
+			return false
+		}
+	}
+
+	for _, c := range reducibleChildren(tok) {
+		if contin := s.reduce(c); !contin {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reduceToken tries the reductions of the given token, which are smaller than its current one, in ascending order and keeps the first reduction with a good feedback. This reduces for example a list below the point of failure of its smallest derivation. False is returned if the channels are closed from outside.
+func (s *minimalDerivationStrategy) reduceToken(tok token.ReduceToken) bool {
+	if tok.Reduces() < 2 {
+		return true
+	}
+
+	original := s.reduction(tok)
+	originalGeneration := s.root.String()
+
+	for reduction := uint(0); reduction < original; reduction++ {
+		s.setReduction(tok, reduction)
+		resetReduced(s.root)
+
+		generation := s.root.String()
+
+		if _, ok := s.bad[generation]; ok || generation == originalGeneration {
+			continue
+		}
+
+		log.Debugf("reduce (%p)%#v to reduction %d", tok, tok, reduction)
+
+		contin, feedback := nextStep(s.root, s.continueReducing, s.feedbackReducing)
+		if !contin {
+			return false
+		} else if feedback == Good {
+			return true
+		}
+
+		s.bad[generation] = struct{}{}
+	}
+
+	s.setReduction(tok, original)
+	resetReduced(s.root)
+
+	return true
+}
+
+// minimize sets the given subtree to its smallest derivation. Every change is recorded with a function which reverts it.
+func (s *minimalDerivationStrategy) minimize(tok token.Token, undo *[]func()) {
+	// a token which minimizes to a simpler token, e.g. a list with only one token, has the same smallest derivation as the simpler token
+	if t, ok := tok.(token.MinimizeToken); ok {
+		if m := t.Minimize(); m != nil {
+			s.minimize(m, undo)
+
+			return
+		}
+	}
+
+	switch t := tok.(type) {
+	case token.ReduceToken:
+		if t.Reduces() >= 2 {
+			if reduction := s.reduction(t); reduction != 0 {
+				s.setReduction(t, 0)
+
+				*undo = append(*undo, func() {
+					s.setReduction(t, reduction)
+				})
+			}
+		}
+	case *lists.One:
+		// every alternative has to be minimized to find the shortest one
+		best, bestLen := 0, -1
+
+		for i := 0; i < t.InternalLen(); i++ {
+			c, _ := t.InternalGet(i)
+
+			s.minimize(c, undo)
+
+			if l := len(c.String()); bestLen == -1 || l < bestLen {
+				best, bestLen = i, l
+			}
+		}
+
+		s.setPermutation(t, uint(best), undo)
+
+		return
+	case *primitives.RangeInt:
+		s.setPermutation(t, 0, undo)
+	case *primitives.CharacterClass:
+		s.setPermutation(t, 0, undo)
+	}
+
+	for _, c := range reducibleChildren(tok) {
+		s.minimize(c, undo)
+	}
+}
+
+// reduction returns the current reduction of the given token
+func (s *minimalDerivationStrategy) reduction(tok token.ReduceToken) uint {
+	if reduction, ok := s.reductions[tok]; ok {
+		return reduction
+	}
+
+	// the last reduction is always the original value
+	return tok.Reduces() - 1
+}
+
+func (s *minimalDerivationStrategy) setReduction(tok token.ReduceToken, reduction uint) {
+	log.Debugf("set (%p)%#v to reduction %d", tok, tok, reduction)
+
+	if err := tok.Reduce(reduction); err != nil {
+		panic(err)
+	}
+
+	s.reductions[tok] = reduction
+}
+
+// setPermutation sets the given permutation if it is not already the current permutation of the token
+func (s *minimalDerivationStrategy) setPermutation(tok token.Token, permutation uint, undo *[]func()) {
+	current := tok.(token.CurrentPermutation).CurrentPermutation()
+	if current == permutation {
+		return
+	}
+
+	if err := tok.Permutation(permutation); err != nil {
+		panic(err)
+	}
+
+	*undo = append(*undo, func() {
+		if err := tok.Permutation(current); err != nil {
+			panic(err)
+		}
+	})
+}
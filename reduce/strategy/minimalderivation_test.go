@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/parser"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestMinimalDerivationStrategy(t *testing.T) {
+	{
+		root := primitives.NewConstantInt(1)
+
+		contin, _, err := NewMinimalDerivation(root)
+		Nil(t, err)
+
+		_, ok := <-contin
+		False(t, ok)
+
+		Equal(t, "1", root.String())
+	}
+	{
+		// Switch alternatives and simplify values
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = Value ";" Value
+
+			Value = Group | Item
+			Group = "(" Number "," Number ")"
+			Item = [a-z]
+
+			$Number Int = from: 1,
+				to: 9
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewMinimalDerivation,
+			tok,
+			"(3,7);q",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "(") {
+					return Good
+				}
+
+				return Bad
+			},
+			[]string{
+				"a;a",
+				"a;q",
+				"(1,1);q",
+				"(1,1);a",
+			},
+			"(1,1);a",
+		)
+	}
+	{
+		// Reduce lists and optionals
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = +1,3(Value) ?("!")
+
+			Value = Group | Item
+			Group = "(" +0,3(Item) ")"
+			Item = "x" | "y"
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewMinimalDerivation,
+			tok,
+			"(yxy)x!",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "(") {
+					return Good
+				}
+
+				return Bad
+			},
+			[]string{
+				"x",
+				"x!",
+				"(yxy)!",
+				"()!",
+				"()",
+			},
+			"()",
+		)
+	}
+	{
+		// Reduce lists below the point of failure
+		tok, err := parser.ParseTavor(bytes.NewBufferString(`
+			START = +1,20(Item)
+
+			Item = "a" | "x"
+		`))
+		Nil(t, err)
+
+		validateTavorReduce(
+			t,
+			NewMinimalDerivation,
+			tok,
+			"aaaaaxaaaa",
+			func(out string) ReduceFeedbackType {
+				if strings.Contains(out, "x") {
+					return Good
+				}
+
+				return Bad
+			},
+			[]string{
+				"a",
+				"x",
+			},
+			"x",
+		)
+	}
+}
+
+func TestMinimalDerivationStrategyLoopDetection(t *testing.T) {
+	testStrategyLoopDetection(t, NewMinimalDerivation)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/option"
 	"github.com/zimmski/tavor/token"
 )
 
@@ -42,7 +44,17 @@ const (
 // The function starts the first step of the reduce strategy returning a channel which controls the step flow and a channel for the feedback of the step. The channel returns a value if the step is complete and waits with calculating the next step until a value is put in and feedback is given. The channels are automatically closed when there are no more steps. The error return argument is not nil if an error occurs during the initialization of the reduce strategy.
 type Strategy func(root token.Token) (chan struct{}, chan<- ReduceFeedbackType, error)
 
+// NewWithOptionsFunc returns a reduce strategy instance for the given option values.
+// The error return argument is not nil, if the values are not valid for the strategy.
+type NewWithOptionsFunc func(values option.Values) (Strategy, error)
+
+type strategyOptions struct {
+	options []option.Option
+	new     NewWithOptionsFunc
+}
+
 var strategyLookup = make(map[string]Strategy)
+var strategyOptionsLookup = make(map[string]strategyOptions)
 
 // New returns a new reduce strategy instance given the registered name of the strategy.
 // The error return argument is not nil, if the name does not exist in the registered reduce strategy list.
@@ -80,3 +92,91 @@ func Register(name string, strat Strategy) {
 
 	strategyLookup[name] = strat
 }
+
+// Options returns the declared options of the reduce strategy with the given registered name.
+func Options(name string) []option.Option {
+	return strategyOptionsLookup[name].options
+}
+
+// NewWithOptions returns a new reduce strategy instance given the registered name of the strategy and the arguments for its options.
+// Options which are not given as argument have their default value. The error return argument is not nil, if the name does not exist in the registered reduce strategy list or if the arguments are not valid for the options of the strategy.
+func NewWithOptions(name string, arguments map[string]string) (Strategy, error) {
+	strat, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := option.Parse(Options(name), arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := strategyOptionsLookup[name]; ok {
+		return o.new(values)
+	}
+
+	return strat, nil
+}
+
+// RegisterOptions registers a reduce strategy instance function with the given name and declared options.
+// The strategy is also registered as a reduce strategy instance with the default values of its options.
+func RegisterOptions(name string, options []option.Option, new NewWithOptionsFunc) {
+	if new == nil {
+		panic("register reduce strategy is nil")
+	}
+
+	values, err := option.Parse(options, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	strat, err := new(values)
+	if err != nil {
+		panic(err)
+	}
+
+	Register(name, strat)
+
+	strategyOptionsLookup[name] = strategyOptions{
+		options: options,
+		new:     new,
+	}
+}
+
+// nextStep completes the current reduce step and waits for its feedback. False is returned if the channels are closed from outside.
+func nextStep(root token.Token, continueReducing chan struct{}, feedbackReducing <-chan ReduceFeedbackType) (bool, ReduceFeedbackType) {
+	resetReduced(root)
+
+	log.Debug("done with reducing step")
+
+	// done with this reduce step
+	continueReducing <- struct{}{}
+
+	// wait until we got feedback to the current state
+	feedback, ok := <-feedbackReducing
+	if ok {
+		log.Debugf("GOT FEEDBACK -> Looks %s", feedback)
+	} else {
+		log.Debug("reducing feedback channel closed from outside")
+
+		return false, Unknown
+	}
+
+	// wait until we are allowed to continue
+	if _, ok := <-continueReducing; !ok {
+		log.Debug("reducing continue channel closed from outside")
+
+		return false, Unknown
+	}
+
+	log.Debug("start reducing step")
+
+	return true, feedback
+}
+
+// resetReduced resets the scopes and the states of the tokens of the given reduced token graph
+func resetReduced(root token.Token) {
+	token.ResetScope(root)
+	token.ResetResetTokens(root)
+	token.ResetScope(root)
+}
@@ -1,10 +1,14 @@
 package strategy
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	. "github.com/zimmski/tavor/test/assert"
 
+	"github.com/zimmski/tavor/option"
+	"github.com/zimmski/tavor/parser"
 	"github.com/zimmski/tavor/token"
 	"github.com/zimmski/tavor/token/constraints"
 	"github.com/zimmski/tavor/token/lists"
@@ -74,6 +78,53 @@ func TestStrategy(t *testing.T) {
 	True(t, caught)
 }
 
+func TestStrategyOptions(t *testing.T) {
+	var steps int
+
+	RegisterOptions("mockOptions", []option.Option{
+		{
+			Name:    "steps",
+			Type:    option.Int,
+			Default: "1",
+		},
+	}, func(values option.Values) (Strategy, error) {
+		steps = values.Int("steps")
+
+		return mockStrategy, nil
+	})
+	Equal(t, 1, steps)
+
+	Equal(t, "steps", Options("mockOptions")[0].Name)
+	Nil(t, Options("mock"))
+
+	strat, err := NewWithOptions("mockOptions", map[string]string{
+		"steps": "3",
+	})
+	NotNil(t, strat)
+	Nil(t, err)
+	Equal(t, 3, steps)
+
+	strat, err = NewWithOptions("mockOptions", map[string]string{
+		"unknown": "3",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrUnknownOption, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("Linear", nil)
+	NotNil(t, strat)
+	Nil(t, err)
+
+	strat, err = NewWithOptions("Linear", map[string]string{
+		"steps": "3",
+	})
+	Nil(t, strat)
+	Equal(t, option.ErrUnknownOption, err.(*option.Error).Type)
+
+	strat, err = NewWithOptions("mockachino", nil)
+	Nil(t, strat)
+	NotNil(t, err)
+}
+
 func testStrategyLoopDetection(t *testing.T, newStrategy Strategy) {
 	var tok *token.Token
 
@@ -144,3 +195,36 @@ func testStrategyLoopDetection(t *testing.T, newStrategy Strategy) {
 		Equal(t, ErrEndlessLoopDetected, err.(*Error).Type)
 	}
 }
+
+func validateTavorReduce(t *testing.T, newStrategy Strategy, tok token.Token, input string, feedback func(out string) ReduceFeedbackType, expected []string, final string) {
+	errs := parser.ParseInternal(tok, bytes.NewBufferString(input))
+	Nil(t, errs)
+	if errs != nil {
+		panic(errs)
+	}
+
+	Equal(t, input, tok.String(), "Generation 0")
+
+	continueReducing, feedbackReducing, err := newStrategy(tok)
+	if err != nil {
+		panic(err)
+	}
+
+	var generations []string
+
+	for i := range continueReducing {
+		out := tok.String()
+
+		generations = append(generations, out)
+
+		feedbackReducing <- feedback(out)
+
+		continueReducing <- i
+	}
+
+	if expected != nil {
+		Equal(t, expected, generations, fmt.Sprintf("Generations of %q", input))
+	}
+
+	Equal(t, final, tok.String(), "Final generation")
+}
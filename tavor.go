@@ -8,3 +8,6 @@ const (
 
 // MaxRepeat determines the maximum copies in graph cycles.
 var MaxRepeat = 2
+
+// UnrollLoops determines if graph cycles are unrolled while parsing formats. If not, cycles are replaced by loop tokens which are expanded on demand.
+var UnrollLoops = true
@@ -1,6 +1,7 @@
 package aggregates
 
 import (
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/token"
@@ -56,6 +57,16 @@ func (a *Len) PermutationsAll() uint {
 	return a.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (a *Len) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (a *Len) PermutationsAllBig() *big.Int {
+	return a.PermutationsBig()
+}
+
 func (a *Len) String() string {
 	return strconv.Itoa(a.token.Len())
 }
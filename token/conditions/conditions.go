@@ -2,6 +2,7 @@ package conditions
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/zimmski/tavor/token"
 	"github.com/zimmski/tavor/token/lists"
@@ -46,6 +47,16 @@ func (c *IfPair) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *IfPair) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *IfPair) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (c *IfPair) String() string {
 	return fmt.Sprintf("(%p)%#v -> (%p)%#v", c.Head, c.Head, c.Body, c.Body)
 }
@@ -154,6 +165,16 @@ func (c *If) PermutationsAll() uint {
 	return c.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *If) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *If) PermutationsAllBig() *big.Int {
+	return c.PermutationsBig()
+}
+
 func (c *If) String() string {
 	for _, pair := range c.Pairs {
 		if pair.Head.Evaluate() {
@@ -2,6 +2,7 @@ package conditions
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/zimmski/tavor/log"
 	"github.com/zimmski/tavor/token"
@@ -60,6 +61,16 @@ func (c *BooleanTrue) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *BooleanTrue) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *BooleanTrue) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (c *BooleanTrue) String() string {
 	return "true"
 }
@@ -151,6 +162,16 @@ func (c *BooleanEqual) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *BooleanEqual) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *BooleanEqual) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (c *BooleanEqual) String() string {
 	return fmt.Sprintf("(%p)%#v == (%p)%#v", c.a, c.a, c.b, c.b)
 }
@@ -261,6 +282,16 @@ func (c *VariableDefined) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *VariableDefined) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *VariableDefined) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (c *VariableDefined) String() string {
 	return fmt.Sprintf("defined(%q)", c.name)
 }
@@ -346,6 +377,16 @@ func (c *ExpressionPointer) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *ExpressionPointer) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *ExpressionPointer) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (c *ExpressionPointer) String() string {
 	return c.token.String()
 }
@@ -1,6 +1,8 @@
 package constraints
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/token"
 )
 
@@ -76,6 +78,16 @@ func (c *Optional) PermutationsAll() uint {
 	return 1 + c.token.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *Optional) PermutationsBig() *big.Int {
+	return big.NewInt(2)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *Optional) PermutationsAllBig() *big.Int {
+	return new(big.Int).Add(big.NewInt(1), c.token.PermutationsAllBig())
+}
+
 func (c *Optional) String() string {
 	if c.value {
 		return ""
@@ -84,6 +96,17 @@ func (c *Optional) String() string {
 	return c.token.String()
 }
 
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (c *Optional) CurrentPermutation() uint {
+	if c.value {
+		return 0
+	}
+
+	return 1
+}
+
 // ForwardToken interface methods
 
 // Get returns the current referenced token
@@ -1,6 +1,7 @@
 package expressions
 
 import (
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/token"
@@ -59,6 +60,16 @@ func (e *AddArithmetic) PermutationsAll() uint {
 	return e.a.PermutationsAll() * e.b.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *AddArithmetic) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *AddArithmetic) PermutationsAllBig() *big.Int {
+	return new(big.Int).Mul(e.a.PermutationsAllBig(), e.b.PermutationsAllBig())
+}
+
 func (e *AddArithmetic) String() string {
 	as := e.a.String()
 	bs := e.b.String()
@@ -183,6 +194,16 @@ func (e *SubArithmetic) PermutationsAll() uint {
 	return e.a.PermutationsAll() * e.b.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *SubArithmetic) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *SubArithmetic) PermutationsAllBig() *big.Int {
+	return new(big.Int).Mul(e.a.PermutationsAllBig(), e.b.PermutationsAllBig())
+}
+
 func (e *SubArithmetic) String() string {
 	as := e.a.String()
 	bs := e.b.String()
@@ -307,6 +328,16 @@ func (e *MulArithmetic) PermutationsAll() uint {
 	return e.a.PermutationsAll() * e.b.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *MulArithmetic) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *MulArithmetic) PermutationsAllBig() *big.Int {
+	return new(big.Int).Mul(e.a.PermutationsAllBig(), e.b.PermutationsAllBig())
+}
+
 func (e *MulArithmetic) String() string {
 	as := e.a.String()
 	bs := e.b.String()
@@ -431,6 +462,16 @@ func (e *DivArithmetic) PermutationsAll() uint {
 	return e.a.PermutationsAll() * e.b.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *DivArithmetic) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *DivArithmetic) PermutationsAllBig() *big.Int {
+	return new(big.Int).Mul(e.a.PermutationsAllBig(), e.b.PermutationsAllBig())
+}
+
 func (e *DivArithmetic) String() string {
 	as := e.a.String()
 	bs := e.b.String()
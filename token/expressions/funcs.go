@@ -1,6 +1,8 @@
 package expressions
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/token"
 )
 
@@ -72,6 +74,16 @@ func (e *FuncExpression) PermutationsAll() uint {
 	return e.permutationsAllFunc(e.state)
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *FuncExpression) PermutationsBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(e.Permutations()))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *FuncExpression) PermutationsAllBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(e.PermutationsAll()))
+}
+
 func (e *FuncExpression) String() string {
 	return e.stringFunc(e.state)
 }
@@ -2,6 +2,8 @@ package expressions
 
 import (
 	"bytes"
+	"math/big"
+
 	"github.com/zimmski/tavor/token/primitives"
 
 	"github.com/zimmski/container/list/linkedlist"
@@ -191,6 +193,16 @@ func (e *Path) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (e *Path) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (e *Path) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (e *Path) String() string {
 	var buffer bytes.Buffer
 
@@ -1,6 +1,8 @@
 package filters
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/token"
 )
 
@@ -76,6 +78,16 @@ func (f *FuncFilter) PermutationsAll() uint {
 	return f.permutationsAllFunc(f.state, f.token)
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (f *FuncFilter) PermutationsBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(f.Permutations()))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (f *FuncFilter) PermutationsAllBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(f.PermutationsAll()))
+}
+
 func (f *FuncFilter) String() string {
 	return f.stringFunc(f.state, f.token)
 }
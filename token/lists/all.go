@@ -2,6 +2,7 @@ package lists
 
 import (
 	"bytes"
+	"math/big"
 
 	"github.com/zimmski/tavor/token"
 )
@@ -84,6 +85,22 @@ func (l *All) PermutationsAll() uint {
 	return sum
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *All) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *All) PermutationsAllBig() *big.Int {
+	sum := l.PermutationsBig()
+
+	for _, tok := range l.tokens {
+		sum.Mul(sum, tok.PermutationsAllBig())
+	}
+
+	return sum
+}
+
 func (l *All) String() string {
 	var buffer bytes.Buffer
 
@@ -24,6 +24,7 @@ func TestAll(t *testing.T) {
 	Equal(t, 2, o.Len())
 	Equal(t, 1, o.Permutations())
 	Equal(t, 1, o.PermutationsAll())
+	Equal(t, "1", o.PermutationsAllBig().String())
 
 	Nil(t, o.Permutation(0))
 	Equal(t, "10abc", o.String())
@@ -44,6 +45,7 @@ func TestAll(t *testing.T) {
 	Equal(t, 3, o.Len())
 	Equal(t, 1, o.Permutations())
 	Equal(t, 2, o.PermutationsAll())
+	Equal(t, "2", o.PermutationsAllBig().String())
 
 	Nil(t, o.Permutation(0))
 	Equal(t, "10abc1", o.String())
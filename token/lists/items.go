@@ -1,6 +1,7 @@
 package lists
 
 import (
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/token"
@@ -61,6 +62,16 @@ func (l *ListItem) PermutationsAll() uint {
 	return l.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *ListItem) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *ListItem) PermutationsAllBig() *big.Int {
+	return l.PermutationsBig()
+}
+
 func (l *ListItem) String() string {
 	i := l.Index()
 
@@ -145,6 +156,16 @@ func (l *IndexItem) PermutationsAll() uint {
 	return l.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *IndexItem) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *IndexItem) PermutationsAllBig() *big.Int {
+	return l.PermutationsBig()
+}
+
 func (l *IndexItem) String() string {
 	return strconv.Itoa(l.token.Index())
 }
@@ -257,6 +278,16 @@ func (l *UniqueItem) PermutationsAll() uint {
 	return l.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *UniqueItem) PermutationsBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(l.Permutations()))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *UniqueItem) PermutationsAllBig() *big.Int {
+	return l.PermutationsBig()
+}
+
 func (l *UniqueItem) String() string {
 	i := l.Index()
 
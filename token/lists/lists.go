@@ -6,6 +6,8 @@ type ListErrorType int
 const (
 	// ListErrorOutOfBound an index not in the bound of available list items was used.
 	ListErrorOutOfBound ListErrorType = iota
+	// ListErrorInvalidWeight a weight which is not positive was used.
+	ListErrorInvalidWeight
 )
 
 // ListError holds a list error
@@ -15,6 +17,8 @@ type ListError struct {
 
 func (err *ListError) Error() string {
 	switch err.Type {
+	case ListErrorInvalidWeight:
+		return "Invalid weight"
 	default:
 		return "Out of bound"
 	}
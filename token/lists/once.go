@@ -2,6 +2,7 @@ package lists
 
 import (
 	"bytes"
+	"math/big"
 
 	"github.com/zimmski/tavor/token"
 )
@@ -120,6 +121,22 @@ func (l *Once) PermutationsAll() uint {
 	return sum
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *Once) PermutationsBig() *big.Int {
+	return new(big.Int).MulRange(1, int64(len(l.tokens)))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *Once) PermutationsAllBig() *big.Int {
+	sum := l.PermutationsBig()
+
+	for _, tok := range l.tokens {
+		sum.Mul(sum, tok.PermutationsAllBig())
+	}
+
+	return sum
+}
+
 func (l *Once) String() string {
 	var buffer bytes.Buffer
 
@@ -132,6 +149,36 @@ func (l *Once) String() string {
 	return buffer.String()
 }
 
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (l *Once) CurrentPermutation() uint {
+	rest := make([]int, len(l.tokens))
+	for j := range rest {
+		rest[j] = j
+	}
+
+	var i uint
+
+	for _, v := range l.values {
+		var pers uint = 1
+		for j := uint(2); j < uint(len(rest)); j++ {
+			pers *= j
+		}
+
+		for ti, r := range rest {
+			if r == v {
+				i += uint(ti) * pers
+				rest = append(rest[:ti], rest[ti+1:]...)
+
+				break
+			}
+		}
+	}
+
+	return i
+}
+
 // List interface methods
 
 // Get returns the current referenced token at the given index. The error return argument is not nil, if the index is out of bound.
@@ -25,6 +25,7 @@ func TestOnce(t *testing.T) {
 	Equal(t, 3, o.Len())
 	Equal(t, 6, o.Permutations())
 	Equal(t, 6, o.PermutationsAll())
+	Equal(t, "6", o.PermutationsAllBig().String())
 
 	i, err := o.Get(0)
 	Nil(t, err)
@@ -57,6 +58,7 @@ func TestOnce(t *testing.T) {
 	Equal(t, 3, o.Len())
 	Equal(t, 6, o.Permutations())
 	Equal(t, 12, o.PermutationsAll())
+	Equal(t, "12", o.PermutationsAllBig().String())
 
 	Nil(t, o.Permutation(1))
 	Equal(t, "101abc", o.String())
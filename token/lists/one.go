@@ -1,14 +1,17 @@
 package lists
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/token"
 )
 
 // One implements a list token which chooses of a set of referenced token exactly one token
 // Every permutation chooses one token out of the token set.
 type One struct {
-	tokens []token.Token
-	value  int
+	tokens  []token.Token
+	value   int
+	weights []int
 }
 
 // NewOne returns a new instance of a One token given the set of tokens
@@ -36,6 +39,11 @@ func (l *One) Clone() token.Token {
 		c.tokens[i] = tok.Clone()
 	}
 
+	if l.weights != nil {
+		c.weights = make([]int, len(l.weights))
+		copy(c.weights, l.weights)
+	}
+
 	return &c
 }
 
@@ -95,10 +103,33 @@ func (l *One) PermutationsAll() uint {
 	return sum
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *One) PermutationsBig() *big.Int {
+	return big.NewInt(int64(len(l.tokens)))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *One) PermutationsAllBig() *big.Int {
+	sum := big.NewInt(0)
+
+	for _, tok := range l.tokens {
+		sum.Add(sum, tok.PermutationsAllBig())
+	}
+
+	return sum
+}
+
 func (l *One) String() string {
 	return l.tokens[l.value].String()
 }
 
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (l *One) CurrentPermutation() uint {
+	return uint(l.value)
+}
+
 // List interface methods
 
 // Get returns the current referenced token at the given index. The error return argument is not nil, if the index is out of bound.
@@ -142,6 +173,9 @@ func (l *One) InternalLogicalRemove(tok token.Token) token.Token {
 			} else {
 				l.tokens = append(l.tokens[:i], l.tokens[i+1:]...)
 			}
+			if l.weights != nil {
+				l.weights = append(l.weights[:i], l.weights[i+1:]...)
+			}
 
 			i--
 		}
@@ -182,3 +216,34 @@ func (l *One) Minimize() token.Token {
 
 	return nil
 }
+
+// Weighted interface methods
+
+// Weights returns the weight of every permutation or nil if all permutations are weighted the same
+func (l *One) Weights() []int {
+	return l.weights
+}
+
+// SetWeights sets the weight of every permutation. A nil argument weights all permutations the same. The error return argument is not nil, if the weights are not suitable.
+func (l *One) SetWeights(weights []int) error {
+	if weights == nil {
+		l.weights = nil
+
+		return nil
+	}
+
+	if len(weights) != len(l.tokens) {
+		return &ListError{ListErrorOutOfBound}
+	}
+
+	for _, w := range weights {
+		if w < 1 {
+			return &ListError{ListErrorInvalidWeight}
+		}
+	}
+
+	l.weights = make([]int, len(weights))
+	copy(l.weights, weights)
+
+	return nil
+}
@@ -24,6 +24,7 @@ func TestOne(t *testing.T) {
 	Equal(t, 1, o.Len())
 	Equal(t, 2, o.Permutations())
 	Equal(t, 2, o.PermutationsAll())
+	Equal(t, "2", o.PermutationsAllBig().String())
 
 	i, err := o.Get(0)
 	Nil(t, err)
@@ -45,6 +46,7 @@ func TestOne(t *testing.T) {
 	Equal(t, 1, o.Len())
 	Equal(t, 1, o.Permutations())
 	Equal(t, 6, o.PermutationsAll())
+	Equal(t, "6", o.PermutationsAllBig().String())
 
 	o2 := o.Clone()
 	Equal(t, o.String(), o2.String())
@@ -54,3 +56,37 @@ func TestOne(t *testing.T) {
 
 	Equal(t, o.Permutation(1).(*token.PermutationError).Type, token.PermutationErrorIndexOutOfBound)
 }
+
+func TestOneWeights(t *testing.T) {
+	a := primitives.NewConstantString("a")
+	b := primitives.NewConstantString("b")
+	c := primitives.NewConstantString("c")
+
+	var tok *token.WeightedToken
+	Implements(t, tok, &One{})
+
+	o := NewOne(a, b, c)
+	Nil(t, o.Weights())
+
+	Equal(t, o.SetWeights([]int{1, 2}).(*ListError).Type, ListErrorOutOfBound)
+	Equal(t, o.SetWeights([]int{1, 0, 2}).(*ListError).Type, ListErrorInvalidWeight)
+	Nil(t, o.Weights())
+
+	Nil(t, o.SetWeights([]int{5, 1, 2}))
+	Equal(t, []int{5, 1, 2}, o.Weights())
+	Equal(t, 3, o.Permutations())
+
+	o2 := o.Clone().(*One)
+	Equal(t, []int{5, 1, 2}, o2.Weights())
+
+	Nil(t, o2.SetWeights([]int{1, 1, 1}))
+	Equal(t, []int{5, 1, 2}, o.Weights())
+
+	Equal(t, o, o.InternalLogicalRemove(b))
+	Equal(t, []int{5, 2}, o.Weights())
+	Nil(t, o.Permutation(1))
+	Equal(t, "c", o.String())
+
+	Nil(t, o.SetWeights(nil))
+	Nil(t, o.Weights())
+}
@@ -3,6 +3,7 @@ package lists
 import (
 	"bytes"
 	"math"
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/token"
@@ -178,6 +179,32 @@ func (l *Repeat) PermutationsAll() uint {
 	return sum
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *Repeat) PermutationsBig() *big.Int {
+	perms := big.NewInt(l.To() - l.From())
+
+	return perms.Add(perms, big.NewInt(1))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *Repeat) PermutationsAllBig() *big.Int {
+	sum := big.NewInt(0)
+	from := l.From()
+
+	if l.From() == 0 {
+		sum.Add(sum, big.NewInt(1))
+		from++
+	}
+
+	tokenPermutations := l.token.PermutationsAllBig()
+
+	for i := from; i <= l.To(); i++ {
+		sum.Add(sum, new(big.Int).Exp(tokenPermutations, big.NewInt(i), nil))
+	}
+
+	return sum
+}
+
 func (l *Repeat) String() string {
 	var buffer bytes.Buffer
 
@@ -190,6 +217,13 @@ func (l *Repeat) String() string {
 	return buffer.String()
 }
 
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (l *Repeat) CurrentPermutation() uint {
+	return uint(int64(len(l.value)) - l.From())
+}
+
 // List interface methods
 
 // Get returns the current referenced token at the given index. The error return argument is not nil, if the index is out of bound.
@@ -23,6 +23,7 @@ func TestRepeat(t *testing.T) {
 	Equal(t, 5, o.Len())
 	Equal(t, 6, o.Permutations())
 	Equal(t, 6, o.PermutationsAll())
+	Equal(t, "6", o.PermutationsAllBig().String())
 
 	i, err := o.Get(0)
 	Nil(t, err)
@@ -45,30 +46,35 @@ func TestRepeat(t *testing.T) {
 	Equal(t, 0, o.Len())
 	Equal(t, 3, o.Permutations())
 	Equal(t, 7, o.PermutationsAll())
+	Equal(t, "7", o.PermutationsAllBig().String())
 
 	o = NewRepeat(primitives.NewRangeInt(1, 2), 1, 2)
 	Equal(t, "1", o.String())
 	Equal(t, 1, o.Len())
 	Equal(t, 2, o.Permutations())
 	Equal(t, 6, o.PermutationsAll())
+	Equal(t, "6", o.PermutationsAllBig().String())
 
 	o = NewRepeat(primitives.NewRangeInt(1, 2), 0, 3)
 	Equal(t, "", o.String())
 	Equal(t, 0, o.Len())
 	Equal(t, 4, o.Permutations())
 	Equal(t, 15, o.PermutationsAll())
+	Equal(t, "15", o.PermutationsAllBig().String())
 
 	o = NewRepeat(primitives.NewRangeInt(1, 2), 1, 3)
 	Equal(t, "1", o.String())
 	Equal(t, 1, o.Len())
 	Equal(t, 3, o.Permutations())
 	Equal(t, 14, o.PermutationsAll())
+	Equal(t, "14", o.PermutationsAllBig().String())
 
 	o = NewRepeat(primitives.NewRangeInt(1, 2), 3, 3)
 	Equal(t, "111", o.String())
 	Equal(t, 3, o.Len())
 	Equal(t, 1, o.Permutations())
 	Equal(t, 8, o.PermutationsAll())
+	Equal(t, "8", o.PermutationsAllBig().String())
 
 	b := primitives.NewRangeInt(1, 3)
 	o = NewRepeat(b, 2, 10)
@@ -76,6 +82,7 @@ func TestRepeat(t *testing.T) {
 	Equal(t, 2, o.Len())
 	Equal(t, 9, o.Permutations())
 	Equal(t, 88569, o.PermutationsAll())
+	Equal(t, "88569", o.PermutationsAllBig().String())
 
 	Nil(t, o.Permutation(0))
 	Equal(t, "11", o.String())
@@ -88,6 +95,10 @@ func TestRepeat(t *testing.T) {
 
 	o2 := o.Clone()
 	Equal(t, o.String(), o2.String())
+
+	// arbitrary-precision permutations do not overflow
+	o = NewRepeat(primitives.NewRangeInt(0, 1<<20-1), 4, 4)
+	Equal(t, "1208925819614629174706176", o.PermutationsAllBig().String())
 }
 
 func TestRepeatReduces(t *testing.T) {
@@ -0,0 +1,165 @@
+package token
+
+import (
+	"math/big"
+)
+
+// SetPermutationIndex sets the token graph to the permutation with the given index.
+// The permutations of a graph are numbered from 0 to PermutationsAllBig() - 1. The permutations of a token are ordered by their own permutation index first. Permutations with the same own permutation are ordered by the indices of the children, where the first child changes the fastest. The error return argument is not nil, if the index is out of bound.
+func SetPermutationIndex(root Token, i *big.Int) error {
+	if i.Sign() < 0 || i.Cmp(root.PermutationsAllBig()) >= 0 {
+		return &PermutationError{
+			Type: PermutationErrorIndexOutOfBound,
+		}
+	}
+
+	return setPermutationIndex(root, new(big.Int).Set(i))
+}
+
+func setPermutationIndex(tok Token, i *big.Int) error {
+	if !hasPermutationChildren(tok) {
+		if !i.IsUint64() || i.Uint64() >= uint64(tok.Permutations()) {
+			return &PermutationError{
+				Type: PermutationErrorIndexOutOfBound,
+			}
+		}
+
+		return tok.Permutation(uint(i.Uint64()))
+	}
+
+	for p := uint(0); p < tok.Permutations(); p++ {
+		if err := tok.Permutation(p); err != nil {
+			return err
+		}
+
+		children := permutationChildren(tok)
+
+		w := big.NewInt(1)
+		for _, c := range children {
+			w.Mul(w, c.PermutationsAllBig())
+		}
+
+		if i.Cmp(w) >= 0 {
+			i.Sub(i, w)
+
+			continue
+		}
+
+		for _, c := range children {
+			ci := new(big.Int)
+			i.DivMod(i, c.PermutationsAllBig(), ci)
+
+			if err := setPermutationIndex(c, ci); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return &PermutationError{
+		Type: PermutationErrorIndexOutOfBound,
+	}
+}
+
+// PermutationIndex returns the index of the current permutation of the token graph which is the inverse of SetPermutationIndex.
+// The graph is set to its permutations while computing the index and is therefore reset to an equal permutation of the graph at the end. The error return argument is not nil, if the current permutation of a token cannot be determined.
+func PermutationIndex(root Token) (*big.Int, error) {
+	i, err := permutationIndex(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SetPermutationIndex(root, i); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func permutationIndex(tok Token) (*big.Int, error) {
+	var p uint
+
+	if tok.Permutations() > 1 {
+		t, ok := tok.(CurrentPermutation)
+		if !ok {
+			return nil, &PermutationError{
+				Type: PermutationErrorUnknownPermutation,
+			}
+		}
+
+		p = t.CurrentPermutation()
+	}
+
+	if !hasPermutationChildren(tok) {
+		return new(big.Int).SetUint64(uint64(p)), nil
+	}
+
+	children := permutationChildren(tok)
+
+	i := big.NewInt(0)
+
+	for j := len(children) - 1; j >= 0; j-- {
+		ci, err := permutationIndex(children[j])
+		if err != nil {
+			return nil, err
+		}
+
+		i.Mul(i, children[j].PermutationsAllBig())
+		i.Add(i, ci)
+	}
+
+	for q := uint(0); q < p; q++ {
+		if err := tok.Permutation(q); err != nil {
+			return nil, err
+		}
+
+		w := big.NewInt(1)
+		for _, c := range permutationChildren(tok) {
+			w.Mul(w, c.PermutationsAllBig())
+		}
+
+		i.Add(i, w)
+	}
+
+	return i, nil
+}
+
+// hasPermutationChildren returns true if the token can have children which are traversed
+func hasPermutationChildren(tok Token) bool {
+	if t, ok := tok.(Follow); ok && !t.Follow() {
+		return false
+	}
+
+	switch tok.(type) {
+	case ForwardToken, ListToken:
+		return true
+	}
+
+	return false
+}
+
+// permutationChildren returns the children of the token which are traversed for its current permutation
+func permutationChildren(tok Token) []Token {
+	if !hasPermutationChildren(tok) {
+		return nil
+	}
+
+	var children []Token
+
+	switch t := tok.(type) {
+	case ForwardToken:
+		if v := t.Get(); v != nil {
+			children = append(children, v)
+		}
+	case ListToken:
+		l := t.Len()
+
+		for i := 0; i < l; i++ {
+			c, _ := t.Get(i)
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
@@ -0,0 +1,59 @@
+package token_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/constraints"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestPermutationIndex(t *testing.T) {
+	root := lists.NewAll(
+		lists.NewOne(
+			primitives.NewConstantString("a"),
+			primitives.NewConstantString("b"),
+		),
+		constraints.NewOptional(primitives.NewRangeInt(1, 3)),
+		lists.NewRepeat(primitives.NewCharacterClass("xy"), 0, 2),
+		lists.NewOnce(
+			primitives.NewConstantString("c"),
+			primitives.NewConstantString("d"),
+			primitives.NewConstantString("e"),
+		),
+	)
+
+	all := root.PermutationsAllBig()
+	Equal(t, "336", all.String())
+
+	seen := make(map[string]struct{})
+
+	for i := int64(0); i < all.Int64(); i++ {
+		Nil(t, token.SetPermutationIndex(root, big.NewInt(i)))
+
+		seen[root.String()] = struct{}{}
+
+		got, err := token.PermutationIndex(root)
+		Nil(t, err)
+		Equal(t, i, got.Int64())
+	}
+
+	// every permutation is distinct
+	Equal(t, 336, len(seen))
+
+	Nil(t, token.SetPermutationIndex(root, big.NewInt(0)))
+	Equal(t, "acde", root.String())
+	Nil(t, token.SetPermutationIndex(root, big.NewInt(1)))
+	Equal(t, "bcde", root.String())
+	Nil(t, token.SetPermutationIndex(root, big.NewInt(2)))
+	Equal(t, "a1cde", root.String())
+	Nil(t, token.SetPermutationIndex(root, big.NewInt(335)))
+	Equal(t, "b3yyedc", root.String())
+
+	Equal(t, token.PermutationErrorIndexOutOfBound, token.SetPermutationIndex(root, big.NewInt(-1)).(*token.PermutationError).Type)
+	Equal(t, token.PermutationErrorIndexOutOfBound, token.SetPermutationIndex(root, all).(*token.PermutationError).Type)
+}
@@ -0,0 +1,332 @@
+package primitives
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/token"
+)
+
+// BinaryInt implements an integer token holding a range of integers which are represented in binary
+// The binary representation is either a fixed amount of bytes in a given byte order or a variable-length integer.
+type BinaryInt struct {
+	from int
+	to   int
+
+	size   int
+	signed bool
+	order  binary.ByteOrder
+
+	value int
+}
+
+// NewBinaryInt returns a new instance of a BinaryInt token with the given range which is represented by size bytes in the given byte order.
+// The error return argument is not nil, if from is bigger than to or the size is not 1, 2, 4 or 8.
+func NewBinaryInt(from, to int, size int, signed bool, order binary.ByteOrder) (*BinaryInt, error) {
+	if from > to {
+		return nil, fmt.Errorf("from %d must not be bigger than to %d", from, to)
+	}
+	if size != 1 && size != 2 && size != 4 && size != 8 {
+		return nil, fmt.Errorf("size %d must be 1, 2, 4 or 8", size)
+	}
+
+	return &BinaryInt{
+		from: from,
+		to:   to,
+
+		size:   size,
+		signed: signed,
+		order:  order,
+
+		value: from,
+	}, nil
+}
+
+// NewVarInt returns a new instance of a BinaryInt token with the given range which is represented as unsigned variable-length integer.
+// The error return argument is not nil, if from is bigger than to or negative.
+func NewVarInt(from, to int) (*BinaryInt, error) {
+	if from > to {
+		return nil, fmt.Errorf("from %d must not be bigger than to %d", from, to)
+	}
+	if from < 0 {
+		return nil, fmt.Errorf("variable-length integers must not be negative")
+	}
+
+	return &BinaryInt{
+		from: from,
+		to:   to,
+
+		size: 0,
+
+		value: from,
+	}, nil
+}
+
+func init() {
+	type binaryType struct {
+		size   int
+		signed bool
+		min    int
+		max    int
+	}
+
+	types := map[string]binaryType{
+		"Int8":   {1, true, math.MinInt8, math.MaxInt8},
+		"UInt8":  {1, false, 0, math.MaxUint8},
+		"Int16":  {2, true, math.MinInt16, math.MaxInt16},
+		"UInt16": {2, false, 0, math.MaxUint16},
+		"Int32":  {4, true, math.MinInt32, math.MaxInt32},
+		"UInt32": {4, false, 0, math.MaxUint32},
+		"Int64":  {8, true, math.MinInt64, math.MaxInt64},
+		"UInt64": {8, false, 0, math.MaxInt64},
+	}
+
+	for name, typ := range types {
+		typ := typ
+
+		orders := map[string]binary.ByteOrder{
+			"LE": binary.LittleEndian,
+			"BE": binary.BigEndian,
+		}
+		if typ.size == 1 {
+			// there is no byte order for single bytes
+			orders = map[string]binary.ByteOrder{
+				"": binary.LittleEndian,
+			}
+		}
+
+		for suffix, order := range orders {
+			order := order
+
+			token.RegisterTyped(name+suffix, func(argParser token.ArgumentsTypedParser) (token.Token, error) {
+				from := argParser.GetInt("from", typ.min)
+				to := argParser.GetInt("to", typ.max)
+
+				if err := argParser.Err(); err != nil {
+					return nil, err
+				}
+
+				if from < typ.min || to > typ.max {
+					return nil, fmt.Errorf("range %d-%d is not within %d-%d", from, to, typ.min, typ.max)
+				}
+
+				tok, err := NewBinaryInt(from, to, typ.size, typ.signed, order)
+				if err != nil {
+					return nil, err
+				}
+
+				return tok, nil
+			})
+		}
+	}
+
+	token.RegisterTyped("VarInt", func(argParser token.ArgumentsTypedParser) (token.Token, error) {
+		from := argParser.GetInt("from", 0)
+		to := argParser.GetInt("to", math.MaxInt64)
+
+		if err := argParser.Err(); err != nil {
+			return nil, err
+		}
+
+		tok, err := NewVarInt(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	})
+}
+
+// From returns the from value of the range
+func (p *BinaryInt) From() int {
+	return p.from
+}
+
+// To returns the to value of the range
+func (p *BinaryInt) To() int {
+	return p.to
+}
+
+// Value returns the current value of the token
+func (p *BinaryInt) Value() int {
+	return p.value
+}
+
+// Token interface methods
+
+// Clone returns a copy of the token and all its children
+func (p *BinaryInt) Clone() token.Token {
+	return &BinaryInt{
+		from: p.from,
+		to:   p.to,
+
+		size:   p.size,
+		signed: p.signed,
+		order:  p.order,
+
+		value: p.value,
+	}
+}
+
+// Parse tries to parse the token beginning from the current position in the parser data.
+// If the parsing is successful the error argument is nil and the next current position after the token is returned.
+func (p *BinaryInt) Parse(pars *token.InternalParser, cur int) (int, []error) {
+	var v int
+	var n int
+
+	if p.size == 0 {
+		u, un := binary.Uvarint([]byte(pars.Data[cur:]))
+		if un == 0 {
+			return cur, []error{&token.ParserError{
+				Message: "expected variable-length integer but got early EOF",
+				Type:    token.ParseErrorUnexpectedEOF,
+
+				Position: pars.GetPosition(cur),
+			}}
+		} else if un < 0 || u > math.MaxInt64 {
+			return cur, []error{&token.ParserError{
+				Message: "variable-length integer overflows",
+				Type:    token.ParseErrorUnexpectedData,
+
+				Position: pars.GetPosition(cur),
+			}}
+		}
+
+		v = int(u)
+		n = un
+	} else {
+		n = p.size
+
+		if cur+n > pars.DataLen {
+			return cur, []error{&token.ParserError{
+				Message: fmt.Sprintf("expected %d bytes but got early EOF", n),
+				Type:    token.ParseErrorUnexpectedEOF,
+
+				Position: pars.GetPosition(cur),
+			}}
+		}
+
+		v = p.decode([]byte(pars.Data[cur : cur+n]))
+	}
+
+	if v < p.from || v > p.to {
+		return cur, []error{&token.ParserError{
+			Message: fmt.Sprintf("expected integer in range %d-%d but got %d", p.from, p.to, v),
+			Type:    token.ParseErrorUnexpectedData,
+
+			Position: pars.GetPosition(cur),
+		}}
+	}
+
+	p.value = v
+
+	log.Debugf("Parsed %d", p.value)
+
+	return cur + n, nil
+}
+
+func (p *BinaryInt) decode(b []byte) int {
+	switch p.size {
+	case 1:
+		if p.signed {
+			return int(int8(b[0]))
+		}
+
+		return int(b[0])
+	case 2:
+		if p.signed {
+			return int(int16(p.order.Uint16(b)))
+		}
+
+		return int(p.order.Uint16(b))
+	case 4:
+		if p.signed {
+			return int(int32(p.order.Uint32(b)))
+		}
+
+		return int(p.order.Uint32(b))
+	default:
+		return int(p.order.Uint64(b))
+	}
+}
+
+func (p *BinaryInt) encode() []byte {
+	if p.size == 0 {
+		b := make([]byte, binary.MaxVarintLen64)
+
+		return b[:binary.PutUvarint(b, uint64(p.value))]
+	}
+
+	b := make([]byte, p.size)
+
+	switch p.size {
+	case 1:
+		b[0] = byte(p.value)
+	case 2:
+		p.order.PutUint16(b, uint16(p.value))
+	case 4:
+		p.order.PutUint32(b, uint32(p.value))
+	default:
+		p.order.PutUint64(b, uint64(p.value))
+	}
+
+	return b
+}
+
+// Permutation sets a specific permutation for this token
+func (p *BinaryInt) Permutation(i uint) error {
+	permutations := p.Permutations()
+
+	if i < 0 || i >= permutations {
+		return &token.PermutationError{
+			Type: token.PermutationErrorIndexOutOfBound,
+		}
+	}
+
+	p.value = p.from + int(i)
+
+	return nil
+}
+
+// Permutations returns the number of permutations for this token
+func (p *BinaryInt) Permutations() uint {
+	perms := uint64(p.to) - uint64(p.from)
+
+	// the permutations must stay addressable by int64 values
+	if perms >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return uint(perms + 1)
+}
+
+// PermutationsAll returns the number of all possible permutations for this token including its children
+func (p *BinaryInt) PermutationsAll() uint {
+	return p.Permutations()
+}
+
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *BinaryInt) PermutationsBig() *big.Int {
+	perms := new(big.Int).Sub(big.NewInt(int64(p.to)), big.NewInt(int64(p.from)))
+
+	return perms.Add(perms, big.NewInt(1))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *BinaryInt) PermutationsAllBig() *big.Int {
+	return p.PermutationsBig()
+}
+
+func (p *BinaryInt) String() string {
+	return string(p.encode())
+}
+
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (p *BinaryInt) CurrentPermutation() uint {
+	return uint(p.value - p.from)
+}
@@ -0,0 +1,90 @@
+package primitives
+
+import (
+	"encoding/binary"
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/token"
+)
+
+func TestBinaryTokensToBeTokens(t *testing.T) {
+	var tok *token.Token
+
+	Implements(t, tok, &BinaryInt{})
+}
+
+func TestBinaryInt(t *testing.T) {
+	o, err := NewBinaryInt(0x1234, 0x1236, 2, false, binary.LittleEndian)
+	Nil(t, err)
+	Equal(t, "\x34\x12", o.String())
+
+	Equal(t, 3, o.Permutations())
+
+	Nil(t, o.Permutation(1))
+	Equal(t, "\x35\x12", o.String())
+
+	Equal(t, o.Permutation(3).(*token.PermutationError).Type, token.PermutationErrorIndexOutOfBound)
+
+	o2 := o.Clone()
+	Equal(t, o.String(), o2.String())
+
+	// big endian
+	o, err = NewBinaryInt(0x01020304, 0x01020304, 4, false, binary.BigEndian)
+	Nil(t, err)
+	Equal(t, "\x01\x02\x03\x04", o.String())
+
+	// signed values
+	o, err = NewBinaryInt(-2, -1, 1, true, binary.LittleEndian)
+	Nil(t, err)
+	Equal(t, "\xfe", o.String())
+
+	p := &token.InternalParser{Data: "\xff", DataLen: 1}
+	next, errs := o.Parse(p, 0)
+	Nil(t, errs)
+	Equal(t, 1, next)
+	Equal(t, -1, o.Value())
+
+	p = &token.InternalParser{Data: "\x00", DataLen: 1}
+	_, errs = o.Parse(p, 0)
+	Equal(t, token.ParseErrorUnexpectedData, errs[0].(*token.ParserError).Type)
+
+	p = &token.InternalParser{Data: "", DataLen: 0}
+	_, errs = o.Parse(p, 0)
+	Equal(t, token.ParseErrorUnexpectedEOF, errs[0].(*token.ParserError).Type)
+
+	// the whole range of 64 bit values stays addressable
+	o, err = NewBinaryInt(-9223372036854775808, 9223372036854775807, 8, true, binary.LittleEndian)
+	Nil(t, err)
+	Equal(t, uint(9223372036854775807), o.Permutations())
+}
+
+func TestVarInt(t *testing.T) {
+	o, err := NewVarInt(127, 300)
+	Nil(t, err)
+	Equal(t, "\x7f", o.String())
+
+	Nil(t, o.Permutation(1))
+	Equal(t, "\x80\x01", o.String())
+
+	p := &token.InternalParser{Data: "\xac\x02", DataLen: 2}
+	next, errs := o.Parse(p, 0)
+	Nil(t, errs)
+	Equal(t, 2, next)
+	Equal(t, 300, o.Value())
+
+	p = &token.InternalParser{Data: "\xac", DataLen: 1}
+	_, errs = o.Parse(p, 0)
+	Equal(t, token.ParseErrorUnexpectedEOF, errs[0].(*token.ParserError).Type)
+
+	// invalid ranges are rejected
+	_, err = NewVarInt(300, 127)
+	NotNil(t, err)
+	_, err = NewVarInt(-1, 127)
+	NotNil(t, err)
+	_, err = NewBinaryInt(2, 1, 2, false, binary.BigEndian)
+	NotNil(t, err)
+	_, err = NewBinaryInt(1, 2, 3, false, binary.BigEndian)
+	NotNil(t, err)
+}
@@ -3,6 +3,7 @@ package primitives
 import (
 	"fmt"
 	"io"
+	"math/big"
 	"strconv"
 	"strings"
 	"unicode"
@@ -245,6 +246,36 @@ PARSING:
 	}
 }
 
+// Characters returns the single characters of the character class which are not part of a range
+func (c *CharacterClass) Characters() []rune {
+	return c.chars
+}
+
+// Ranges returns the character ranges of the character class as pairs of their first and last character
+func (c *CharacterClass) Ranges() [][2]rune {
+	ranges := make([][2]rune, len(c.charRanges))
+	for i, r := range c.charRanges {
+		ranges[i] = [2]rune{r.from, r.to}
+	}
+
+	return ranges
+}
+
+// Contains returns true if the given character is part of the character class
+func (c *CharacterClass) Contains(v rune) bool {
+	if _, ok := c.charsLookup[v]; ok {
+		return true
+	}
+
+	for _, r := range c.charRanges {
+		if v >= r.from && v <= r.to {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Clone returns a copy of the token and all its children
 func (c *CharacterClass) Clone() token.Token {
 	chars := make([]rune, len(c.chars))
@@ -370,6 +401,39 @@ func (c *CharacterClass) PermutationsAll() uint {
 	return c.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (c *CharacterClass) PermutationsBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(c.permutations))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (c *CharacterClass) PermutationsAllBig() *big.Int {
+	return c.PermutationsBig()
+}
+
 func (c *CharacterClass) String() string {
 	return string(c.value)
 }
+
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (c *CharacterClass) CurrentPermutation() uint {
+	for i, v := range c.chars {
+		if v == c.value {
+			return uint(i)
+		}
+	}
+
+	i := uint(len(c.chars))
+
+	for _, v := range c.charRanges {
+		if c.value >= v.from && c.value <= v.to {
+			return i + uint(c.value-v.from)
+		}
+
+		i += uint(v.to-v.from) + 1
+	}
+
+	return 0
+}
@@ -85,4 +85,13 @@ func TestCharacterClass(t *testing.T) {
 
 	o2 := o.Clone()
 	Equal(t, o.String(), o2.String())
+
+	o = NewCharacterClass(`xa-f0-9`)
+	Equal(t, []rune{'x'}, o.Characters())
+	Equal(t, [][2]rune{{'a', 'f'}, {'0', '9'}}, o.Ranges())
+	True(t, o.Contains('x'))
+	True(t, o.Contains('c'))
+	True(t, o.Contains('9'))
+	False(t, o.Contains('g'))
+	False(t, o.Contains('/'))
 }
@@ -3,6 +3,7 @@ package primitives
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/log"
@@ -96,6 +97,16 @@ func (p *ConstantInt) PermutationsAll() uint {
 	return p.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *ConstantInt) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *ConstantInt) PermutationsAllBig() *big.Int {
+	return p.PermutationsBig()
+}
+
 func (p *ConstantInt) String() string {
 	return strconv.Itoa(p.value)
 }
@@ -283,6 +294,26 @@ func (p *RangeInt) PermutationsAll() uint {
 	return p.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *RangeInt) PermutationsBig() *big.Int {
+	perms := new(big.Int).Sub(big.NewInt(int64(p.to)), big.NewInt(int64(p.from)))
+	perms.Quo(perms, big.NewInt(int64(p.step)))
+
+	return perms.Add(perms, big.NewInt(1))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *RangeInt) PermutationsAllBig() *big.Int {
+	return p.PermutationsBig()
+}
+
 func (p *RangeInt) String() string {
 	return strconv.Itoa(p.value)
 }
+
+// CurrentPermutation interface methods
+
+// CurrentPermutation returns the current permutation of the token
+func (p *RangeInt) CurrentPermutation() uint {
+	return uint((p.value - p.from) / p.step)
+}
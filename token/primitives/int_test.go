@@ -1,6 +1,7 @@
 package primitives
 
 import (
+	"math"
 	"testing"
 
 	. "github.com/zimmski/tavor/test/assert"
@@ -35,6 +36,7 @@ func TestRangeInt(t *testing.T) {
 	Equal(t, "2", o.String())
 
 	Equal(t, 3, o.Permutations())
+	Equal(t, "3", o.PermutationsBig().String())
 
 	Nil(t, o.Permutation(0))
 	Equal(t, "2", o.String())
@@ -43,6 +45,10 @@ func TestRangeInt(t *testing.T) {
 
 	Equal(t, o.Permutation(3).(*token.PermutationError).Type, token.PermutationErrorIndexOutOfBound)
 
+	// the whole integer range does not fit into the regular permutation count
+	o = NewRangeInt(math.MinInt64, math.MaxInt64)
+	Equal(t, "18446744073709551616", o.PermutationsAllBig().String())
+
 	o2 := o.Clone()
 	Equal(t, o.String(), o2.String())
 
@@ -0,0 +1,296 @@
+package primitives
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/zimmski/tavor"
+	"github.com/zimmski/tavor/log"
+	"github.com/zimmski/tavor/token"
+)
+
+// Loop implements a lazy pointer token which references a token that can lead back to the loop itself
+// The referenced token is cloned on first use. A loop is expanded at most tavor.MaxRepeat times in a row for the same referenced token. Loops which cannot be expanded anymore are removed from the expanded graph so that every reachable loop is always expandable.
+type Loop struct {
+	origin token.Token
+	token  token.Token
+
+	counts     map[token.Token]int
+	expansions *loopExpansions
+
+	parent *Loop
+	start  int
+}
+
+type loopExpansions struct {
+	prototypes map[string]token.Token
+}
+
+// NewLoop returns a new instance of a Loop token referencing the given token
+func NewLoop(origin token.Token) *Loop {
+	return &Loop{
+		origin: origin,
+
+		counts: make(map[token.Token]int),
+		expansions: &loopExpansions{
+			prototypes: make(map[string]token.Token),
+		},
+
+		start: -1,
+	}
+}
+
+// Origin returns the token which is referenced by the loop
+func (l *Loop) Origin() token.Token {
+	return l.origin
+}
+
+// Token interface methods
+
+// Clone returns a copy of the token without its expansion
+func (l *Loop) Clone() token.Token {
+	return &Loop{
+		origin: l.origin, // do not clone further
+
+		counts:     l.counts,
+		expansions: l.expansions,
+
+		parent: l.parent,
+		start:  -1,
+	}
+}
+
+// Parse tries to parse the token beginning from the current position in the parser data.
+// If the parsing is successful the error argument is nil and the next current position after the token is returned.
+func (l *Loop) Parse(pars *token.InternalParser, cur int) (int, []error) {
+	for p := l.parent; p != nil; p = p.parent {
+		if p.origin == l.origin && p.start == cur {
+			return cur, []error{&token.ParserError{
+				Message: "loop does not consume any data",
+				Type:    token.ParseErrEndlessLoopDetected,
+
+				Position: pars.GetPosition(cur),
+			}}
+		}
+	}
+
+	// parsing is only bound by the data and not by the maximum of repetitions
+	tok := l.origin.Clone()
+	l.expansions.prepare(tok, l.increment(), l, -1)
+
+	l.start = cur
+	nex, errs := tok.Parse(pars, cur)
+	l.start = -1
+
+	if len(errs) > 0 {
+		return cur, errs
+	}
+
+	l.token = tok
+
+	return nex, nil
+}
+
+// Permutation sets a specific permutation for this token
+func (l *Loop) Permutation(i uint) error {
+	permutations := l.Permutations()
+
+	if i < 0 || i >= permutations {
+		return &token.PermutationError{
+			Type: token.PermutationErrorIndexOutOfBound,
+		}
+	}
+
+	// do nothing
+
+	return nil
+}
+
+// Permutations returns the number of permutations for this token
+func (l *Loop) Permutations() uint {
+	return 1
+}
+
+// PermutationsAll returns the number of all possible permutations for this token including its children
+func (l *Loop) PermutationsAll() uint {
+	tok := l.Get()
+	if tok == nil {
+		return 0
+	}
+
+	return tok.PermutationsAll()
+}
+
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (l *Loop) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (l *Loop) PermutationsAllBig() *big.Int {
+	tok := l.Get()
+	if tok == nil {
+		return big.NewInt(0)
+	}
+
+	return tok.PermutationsAllBig()
+}
+
+func (l *Loop) String() string {
+	tok := l.Get()
+	if tok == nil {
+		return ""
+	}
+
+	return tok.String()
+}
+
+// ForwardToken interface methods
+
+// Get returns the current referenced token which is expanded on first use. Nil is returned if the loop cannot be expanded.
+func (l *Loop) Get() token.Token {
+	if l.token == nil {
+		if p := l.expansions.prototype(l.origin, l.increment()); p != nil {
+			l.token = p.Clone()
+		}
+	}
+
+	return l.token
+}
+
+// InternalGet returns the current referenced internal token which is nil if the loop is not expanded
+func (l *Loop) InternalGet() token.Token {
+	return l.token
+}
+
+// InternalLogicalRemove removes the referenced internal token and returns the replacement for the current token or nil if the current token should be removed.
+func (l *Loop) InternalLogicalRemove(tok token.Token) token.Token {
+	if l.token == tok {
+		return nil
+	}
+
+	return l
+}
+
+// InternalReplace replaces an old with a new internal token if it is referenced by this token. The error return argument is not nil, if the replacement is not suitable.
+func (l *Loop) InternalReplace(oldToken, newToken token.Token) error {
+	if l.token == oldToken {
+		l.token = newToken
+	}
+
+	return nil
+}
+
+// Loop interface methods
+
+// Expanded returns if the referenced token was already created
+func (l *Loop) Expanded() bool {
+	return l.token != nil
+}
+
+// Minimize interface methods
+
+// Minimize tries to minimize itself and returns a token if it was successful, or nil if there was nothing to minimize
+func (l *Loop) Minimize() token.Token {
+	// a loop must stay a loop or we lose the laziness
+
+	return nil
+}
+
+func (l *Loop) increment() map[token.Token]int {
+	counts := make(map[token.Token]int, len(l.counts)+1)
+	for k, v := range l.counts {
+		counts[k] = v
+	}
+
+	counts[l.origin]++
+
+	return counts
+}
+
+// prototype returns the expansion of the given origin for the given counts of expansions, or nil if there is no valid expansion
+func (e *loopExpansions) prototype(origin token.Token, counts map[token.Token]int) token.Token {
+	if counts[origin] > tavor.MaxRepeat {
+		return nil
+	}
+
+	keys := make([]string, 0, len(counts))
+	for k, v := range counts {
+		keys = append(keys, fmt.Sprintf("%p:%d", k, v))
+	}
+	sort.Strings(keys)
+
+	key := fmt.Sprintf("%p|%d|%s", origin, tavor.MaxRepeat, strings.Join(keys, ","))
+
+	if p, ok := e.prototypes[key]; ok {
+		return p
+	}
+
+	log.Debugf("expand loop of (%p)%#v with %s", origin, origin, key)
+
+	p := origin.Clone()
+	if !e.prepare(p, counts, nil, tavor.MaxRepeat) {
+		p = nil
+	}
+
+	e.prototypes[key] = p
+
+	return p
+}
+
+// prepare sets the counts of all loops in the given token graph. If maxRepeat is not negative, loops which cannot be expanded are removed. False is returned if the token itself must be removed.
+func (e *loopExpansions) prepare(tok token.Token, counts map[token.Token]int, parent *Loop, maxRepeat int) bool {
+	if l, ok := tok.(*Loop); ok {
+		l.counts = counts
+		l.expansions = e
+		l.parent = parent
+
+		return maxRepeat < 0 || e.prototype(l.origin, l.increment()) != nil
+	}
+
+	if t, ok := tok.(token.Follow); ok && !t.Follow() {
+		return true
+	}
+
+	switch t := tok.(type) {
+	case token.ForwardToken:
+		c := t.InternalGet()
+		if c == nil {
+			break
+		}
+
+		if !e.prepare(c, counts, parent, maxRepeat) {
+			log.Debugf("remove (%p)%#v from (%p)%#v", c, c, t, t)
+
+			return t.InternalLogicalRemove(c) != nil
+		}
+
+		// force regeneration of possible cloned tokens
+		if err := t.InternalReplace(c, c); err != nil {
+			panic(err)
+		}
+	case token.ListToken:
+		for i := t.InternalLen() - 1; i >= 0; i-- {
+			c, _ := t.InternalGet(i)
+
+			if !e.prepare(c, counts, parent, maxRepeat) {
+				log.Debugf("remove (%p)%#v from (%p)%#v", c, c, t, t)
+
+				if t.InternalLogicalRemove(c) == nil {
+					return false
+				}
+
+				continue
+			}
+
+			// force regeneration of possible cloned tokens
+			if err := t.InternalReplace(c, c); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return true
+}
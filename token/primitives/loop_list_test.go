@@ -0,0 +1,106 @@
+package primitives_test
+
+import (
+	"testing"
+
+	. "github.com/zimmski/tavor/test/assert"
+
+	"github.com/zimmski/tavor/token"
+	"github.com/zimmski/tavor/token/lists"
+	"github.com/zimmski/tavor/token/primitives"
+)
+
+func TestLoopTokensToBeTokens(t *testing.T) {
+	var tok *token.Token
+
+	Implements(t, tok, &primitives.Loop{})
+
+	var forward *token.ForwardToken
+
+	Implements(t, forward, &primitives.Loop{})
+
+	var loop *token.LoopToken
+
+	Implements(t, loop, &primitives.Loop{})
+}
+
+func TestLoop(t *testing.T) {
+	// A = "(" A ")" | "x"
+	placeholder := primitives.NewConstantString("A")
+	all := lists.NewAll(
+		primitives.NewConstantString("("),
+		placeholder,
+		primitives.NewConstantString(")"),
+	)
+	a := lists.NewOne(
+		all,
+		primitives.NewConstantString("x"),
+	)
+
+	l := primitives.NewLoop(a)
+	Nil(t, all.InternalReplace(placeholder, l))
+
+	Equal(t, a, l.Origin())
+	False(t, l.Expanded())
+	Nil(t, l.InternalGet())
+	Equal(t, 1, l.Permutations())
+
+	// the loop is expanded on first use at maximum tavor.MaxRepeat times
+	Equal(t, "((x))", a.String())
+	True(t, l.Expanded())
+	Equal(t, 3, a.PermutationsAll())
+
+	// clones are not expanded
+	c := l.Clone().(*primitives.Loop)
+	Nil(t, c.InternalGet())
+	Equal(t, "(x)", c.String())
+
+	Equal(t, l.Permutation(1).(*token.PermutationError).Type, token.PermutationErrorIndexOutOfBound)
+
+	// parsing is not bound by the maximum of repetitions
+	{
+		data := "((((x))))"
+		pars := &token.InternalParser{
+			Data:    data,
+			DataLen: len(data),
+		}
+
+		nex, errs := a.Clone().Parse(pars, 0)
+		Nil(t, errs)
+		Equal(t, len(data), nex)
+
+		tok := a.Clone()
+		nex, errs = tok.Parse(pars, 0)
+		Nil(t, errs)
+		Equal(t, len(data), nex)
+		Equal(t, data, tok.String())
+	}
+}
+
+func TestLoopLeftRecursion(t *testing.T) {
+	// A = A "a" | "b"
+	placeholder := primitives.NewConstantString("A")
+	all := lists.NewAll(
+		placeholder,
+		primitives.NewConstantString("a"),
+	)
+	a := lists.NewOne(
+		all,
+		primitives.NewConstantString("b"),
+	)
+
+	l := primitives.NewLoop(a)
+	Nil(t, all.InternalReplace(placeholder, l))
+
+	data := "ba"
+	pars := &token.InternalParser{
+		Data:    data,
+		DataLen: len(data),
+	}
+
+	// loops which do not consume any data are not followed
+	nex, errs := a.Parse(pars, 0)
+	Nil(t, errs)
+	Equal(t, len(data), nex)
+	Equal(t, data, a.String())
+}
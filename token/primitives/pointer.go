@@ -3,6 +3,7 @@ package primitives
 import (
 	"fmt"
 	"github.com/zimmski/tavor/token"
+	"math/big"
 	"reflect"
 )
 
@@ -120,6 +121,24 @@ func (p *Pointer) PermutationsAll() uint {
 	return p.token.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *Pointer) PermutationsBig() *big.Int {
+	p.cloneOnFirstUse()
+
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *Pointer) PermutationsAllBig() *big.Int {
+	p.cloneOnFirstUse()
+
+	if p.token == nil {
+		panic("Pointer token does not have a referencing token")
+	}
+
+	return p.token.PermutationsAllBig()
+}
+
 func (p *Pointer) String() string {
 	if p.token == nil {
 		panic("Pointer token does not have a referencing token")
@@ -1,11 +1,14 @@
 package primitives
 
 import (
+	"math/big"
+
 	"github.com/zimmski/tavor/token"
 )
 
 // Scope implements a general scope token which references a token
 type Scope struct {
+	names []string
 	token token.Token
 }
 
@@ -16,11 +19,39 @@ func NewScope(tok token.Token) *Scope {
 	}
 }
 
+// NewNamedScope returns a new instance of a Scope token with the given name, e.g. the name of a token definition
+func NewNamedScope(name string, tok token.Token) *Scope {
+	return NewMultiNamedScope([]string{name}, tok)
+}
+
+// NewMultiNamedScope returns a new instance of a Scope token with the given names beginning with the outermost, e.g. the names of a token definition and its aliases
+func NewMultiNamedScope(names []string, tok token.Token) *Scope {
+	return &Scope{
+		names: names,
+		token: tok,
+	}
+}
+
+// Name returns the outermost name of the scope or an empty string if the scope has no name
+func (p *Scope) Name() string {
+	if len(p.names) == 0 {
+		return ""
+	}
+
+	return p.names[0]
+}
+
+// Names returns all names of the scope beginning with the outermost
+func (p *Scope) Names() []string {
+	return p.names
+}
+
 // Token interface methods
 
 // Clone returns a copy of the token and all its children
 func (p *Scope) Clone() token.Token {
 	return &Scope{
+		names: p.names,
 		token: p.token.Clone(),
 	}
 }
@@ -56,6 +87,16 @@ func (p *Scope) PermutationsAll() uint {
 	return p.token.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *Scope) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *Scope) PermutationsAllBig() *big.Int {
+	return p.token.PermutationsAllBig()
+}
+
 func (p *Scope) String() string {
 	return p.token.String()
 }
@@ -94,8 +135,17 @@ func (p *Scope) InternalReplace(oldToken, newToken token.Token) error {
 
 // Minimize tries to minimize itself and returns a token if it was successful, or nil if there was nothing to minimize
 func (p *Scope) Minimize() token.Token {
-	if _, ok := p.token.(*Scope); ok {
-		return p.token
+	if t, ok := p.token.(*Scope); ok {
+		if len(p.names) == 0 {
+			return t
+		}
+
+		// keep the names of both scopes, e.g. for a token definition which is an alias of another one
+		names := make([]string, 0, len(p.names)+len(t.names))
+		names = append(names, p.names...)
+		names = append(names, t.names...)
+
+		return NewMultiNamedScope(names, t.token)
 	}
 
 	return nil
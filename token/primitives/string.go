@@ -2,6 +2,7 @@ package primitives
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/zimmski/tavor/log"
 	"github.com/zimmski/tavor/token"
@@ -81,6 +82,16 @@ func (p *ConstantString) PermutationsAll() uint {
 	return p.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (p *ConstantString) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (p *ConstantString) PermutationsAllBig() *big.Int {
+	return p.PermutationsBig()
+}
+
 func (p *ConstantString) String() string {
 	return p.value
 }
@@ -2,6 +2,7 @@ package sequences
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/token"
@@ -177,6 +178,12 @@ func (s *Sequence) Permutations() uint { panic("unusable token") }
 // PermutationsAll returns the number of all possible permutations for this token including its children
 func (s *Sequence) PermutationsAll() uint { panic("unusable token") }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (s *Sequence) PermutationsBig() *big.Int { panic("unusable token") }
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (s *Sequence) PermutationsAllBig() *big.Int { panic("unusable token") }
+
 func (s *Sequence) String() string { panic("unusable token") }
 
 // SequenceItem implements a sequence item token which holds one distinct value of the sequence
@@ -229,6 +236,16 @@ func (s *SequenceItem) PermutationsAll() uint {
 	return s.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (s *SequenceItem) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (s *SequenceItem) PermutationsAllBig() *big.Int {
+	return s.PermutationsBig()
+}
+
 func (s *SequenceItem) String() string {
 	return strconv.Itoa(s.value)
 }
@@ -305,6 +322,16 @@ func (s *SequenceExistingItem) PermutationsAll() uint {
 	return s.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (s *SequenceExistingItem) PermutationsBig() *big.Int {
+	return new(big.Int).SetUint64(uint64(s.Permutations()))
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (s *SequenceExistingItem) PermutationsAllBig() *big.Int {
+	return s.PermutationsBig()
+}
+
 func (s *SequenceExistingItem) String() string {
 	return strconv.Itoa(s.value)
 }
@@ -434,6 +461,16 @@ func (s *SequenceResetItem) PermutationsAll() uint {
 	return s.Permutations()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (s *SequenceResetItem) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (s *SequenceResetItem) PermutationsAllBig() *big.Int {
+	return s.PermutationsBig()
+}
+
 func (s *SequenceResetItem) String() string {
 	return ""
 }
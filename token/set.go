@@ -85,6 +85,11 @@ func SetScope(root Token, scope *VariableScope) {
 			continue
 		}
 
+		if t, ok := tok.(Loop); ok && !t.Expanded() {
+			// do not create loop tokens just for the sake of setting scopes
+			continue
+		}
+
 		switch t := tok.(type) {
 		case ForwardToken:
 			if v := t.Get(); v != nil {
@@ -228,7 +233,9 @@ func SetCombinedScope(root Token, scope *VariableScope) {
 					checked[v] = struct{}{}
 				}
 			}
-			if v := t.Get(); v != nil {
+			if l, ok := t.(Loop); ok && !l.Expanded() {
+				// do not create loop tokens just for the sake of setting scopes
+			} else if v := t.Get(); v != nil {
 				if _, ok := checked[v]; !ok {
 					queue.Unshift(set{
 						token: v,
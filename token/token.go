@@ -2,6 +2,7 @@ package token
 
 import (
 	"fmt"
+	"math/big"
 	"text/scanner"
 )
 
@@ -18,6 +19,10 @@ type Token interface {
 	Permutations() uint
 	// PermutationsAll returns the number of all possible permutations for this token including its children
 	PermutationsAll() uint
+	// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+	PermutationsBig() *big.Int
+	// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+	PermutationsAllBig() *big.Int
 
 	// Parse tries to parse the token beginning from the current position in the parser data.
 	// If the parsing is successful the error argument is nil and the next current position after the token is returned.
@@ -46,6 +51,12 @@ type ListToken interface {
 	InternalReplace
 }
 
+// CurrentPermutation defines a token which can report its current permutation
+type CurrentPermutation interface {
+	// CurrentPermutation returns the current permutation of the token
+	CurrentPermutation() uint
+}
+
 // Follow defines if the children of a token should be traversed
 type Follow interface {
 	// Follow returns if the children of the token should be traversed
@@ -94,6 +105,20 @@ type LenToken interface {
 	Len
 }
 
+// Loop defines a forward token which references a token that can lead back to the loop itself
+type Loop interface {
+	Forward
+
+	// Expanded returns if the referenced token was already created
+	Expanded() bool
+}
+
+// LoopToken combines the Token and Loop interface
+type LoopToken interface {
+	Token
+	Loop
+}
+
 // Minimize defines a minimize token which has methods to reduce itself to easier constructs
 type Minimize interface {
 	// Minimize tries to minimize itself and returns a token if it was successful, or nil if there was nothing to minimize
@@ -223,6 +248,20 @@ type VariableToken interface {
 	Variable
 }
 
+// Weighted defines a token which can weight its permutations
+type Weighted interface {
+	// Weights returns the weight of every permutation or nil if all permutations are weighted the same
+	Weights() []int
+	// SetWeights sets the weight of every permutation. The error return argument is not nil, if the weights are not suitable.
+	SetWeights(weights []int) error
+}
+
+// WeightedToken combines the Token and Weighted interface
+type WeightedToken interface {
+	Token
+	Weighted
+}
+
 ////////////////////////
 
 // TODO put this somewhere else?
@@ -233,6 +272,8 @@ type PermutationErrorType int
 const (
 	// PermutationErrorIndexOutOfBound an index not in the bound of available permutations was used.
 	PermutationErrorIndexOutOfBound PermutationErrorType = iota
+	// PermutationErrorUnknownPermutation the current permutation of a token cannot be determined.
+	PermutationErrorUnknownPermutation
 )
 
 // PermutationError holds a permutation error
@@ -244,6 +285,8 @@ func (err *PermutationError) Error() string {
 	switch err.Type {
 	case PermutationErrorIndexOutOfBound:
 		return "permutation index out of bound"
+	case PermutationErrorUnknownPermutation:
+		return "current permutation is unknown"
 	default:
 		return fmt.Sprintf("unknown permutation error type %#v", err.Type)
 	}
@@ -459,6 +502,12 @@ const (
 	ParseErrorUnexpectedEOF
 	// ParseErrorUnexpectedData additional data was not expected
 	ParseErrorUnexpectedData
+	// ParseErrorFunctionArgumentCount the function was called with a wrong number of arguments
+	ParseErrorFunctionArgumentCount
+	// ParseErrorFunctionNotDefined there is no function with this name
+	ParseErrorFunctionNotDefined
+	// ParseErrorUnknownFunctionParameter the function parameter is unknown
+	ParseErrorUnknownFunctionParameter
 )
 
 // ParserError holds a parser error
@@ -1,6 +1,7 @@
 package variables
 
 import (
+	"math/big"
 	"strconv"
 
 	"github.com/zimmski/tavor/log"
@@ -76,6 +77,16 @@ func (v *Variable) PermutationsAll() uint {
 	return v.token.PermutationsAll()
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (v *Variable) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (v *Variable) PermutationsAllBig() *big.Int {
+	return v.token.PermutationsAllBig()
+}
+
 func (v *Variable) String() string {
 	return v.token.String()
 }
@@ -175,6 +186,16 @@ func (v *VariableItem) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (v *VariableItem) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (v *VariableItem) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (v *VariableItem) String() string {
 	i := v.Index()
 
@@ -347,6 +368,16 @@ func (v *VariableReference) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (v *VariableReference) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (v *VariableReference) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (v *VariableReference) String() string {
 	return ""
 }
@@ -430,6 +461,16 @@ func (v *VariableValue) PermutationsAll() uint {
 	return 1
 }
 
+// PermutationsBig returns the number of permutations for this token as arbitrary-precision integer
+func (v *VariableValue) PermutationsBig() *big.Int {
+	return big.NewInt(1)
+}
+
+// PermutationsAllBig returns the number of all possible permutations for this token including its children as arbitrary-precision integer
+func (v *VariableValue) PermutationsAllBig() *big.Int {
+	return big.NewInt(1)
+}
+
 func (v *VariableValue) String() string {
 	return v.variable.InternalGet().String()
 }